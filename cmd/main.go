@@ -1,10 +1,19 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/Darari17/be-tickitz-full/internal/configs"
 	"github.com/Darari17/be-tickitz-full/internal/routers"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/Darari17/be-tickitz-full/internal/workers"
+	"github.com/Darari17/be-tickitz-full/pkg"
 	"github.com/joho/godotenv"
 )
 
@@ -23,6 +32,14 @@ func main() {
 		return
 	}
 
+	// load jwt signing keys
+	keyring, err := pkg.KeyringFromEnv()
+	if err != nil {
+		log.Println("Failed to load JWT keys.\nCause:", err.Error())
+		return
+	}
+	pkg.SetKeyring(keyring)
+
 	// init db
 	db, err := configs.InitDB()
 	if err != nil {
@@ -47,7 +64,33 @@ func main() {
 	log.Println("Redis Connected.")
 	defer rdb.Close()
 
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// background jobs
+	scheduler := workers.InitScheduler(db, rdb)
+	if utils.GetEnvBool("WORKERS_ENABLED", true) {
+		scheduler.Start(ctx)
+		log.Println("Workers Started.")
+	}
+
 	// router
 	router := routers.InitRouter(db, rdb)
-	router.Run(":8080")
+	server := &http.Server{Addr: ":8080", Handler: router}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Println("Server stopped.\nCause:", err.Error())
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down.")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Failed to shut down server.\nCause:", err.Error())
+	}
+	scheduler.Stop()
 }
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS order_seats_schedule_seat_key;
+
+ALTER TABLE
+  public.order_seats
+DROP
+  COLUMN IF EXISTS schedules_id;
\ No newline at end of file
@@ -0,0 +1,22 @@
+ALTER TABLE
+  public.order_seats
+ADD
+  COLUMN schedules_id integer NULL;
+
+UPDATE
+  public.order_seats os
+SET
+  schedules_id = o.schedules_id
+FROM
+  public.orders o
+WHERE
+  o.id = os.orders_id;
+
+ALTER TABLE
+  public.order_seats
+ALTER COLUMN
+  schedules_id
+SET
+  NOT NULL;
+
+CREATE UNIQUE INDEX order_seats_schedule_seat_key ON public.order_seats (schedules_id, seats_id);
\ No newline at end of file
@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS order_seats_schedule_seat_key;
+
+DELETE FROM
+  public.order_seats
+WHERE
+  released_at IS NOT NULL;
+
+CREATE UNIQUE INDEX order_seats_schedule_seat_key ON public.order_seats (schedules_id, seats_id);
+
+ALTER TABLE
+  public.order_seats
+DROP
+  COLUMN IF EXISTS released_at;
+
+ALTER TABLE
+  public.orders
+DROP
+  COLUMN IF EXISTS status;
\ No newline at end of file
@@ -0,0 +1,20 @@
+ALTER TABLE
+  public.orders
+ADD
+  COLUMN status character varying(20) NOT NULL DEFAULT 'pending';
+
+UPDATE
+  public.orders
+SET
+  status = 'paid';
+
+ALTER TABLE
+  public.order_seats
+ADD
+  COLUMN released_at timestamp without time zone NULL;
+
+DROP INDEX IF EXISTS order_seats_schedule_seat_key;
+
+CREATE UNIQUE INDEX order_seats_schedule_seat_key ON public.order_seats (schedules_id, seats_id)
+WHERE
+  released_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE
+  public.order_status_history (
+    id integer NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    orders_id integer NOT NULL,
+    from_status character varying(20) NULL,
+    to_status character varying(20) NOT NULL,
+    note text NULL,
+    created_at timestamp without time zone NOT NULL DEFAULT now()
+  );
+
+ALTER TABLE
+  public.order_status_history
+ADD
+  CONSTRAINT order_status_history_pkey PRIMARY KEY (id);
+
+CREATE INDEX order_status_history_orders_id_idx ON public.order_status_history (orders_id);
\ No newline at end of file
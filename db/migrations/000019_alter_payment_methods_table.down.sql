@@ -0,0 +1,4 @@
+ALTER TABLE
+  public.payment_methods
+DROP
+  COLUMN IF EXISTS provider;
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE
+  public.payment_methods
+ADD
+  COLUMN provider character varying(50) NULL;
+
+-- Existing methods get no provider, so nobody can pay with them until an operator maps
+-- each one to an enabled gateway: UPDATE payment_methods SET provider = '<name>' WHERE id = <id>.
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS payment_transactions;
\ No newline at end of file
@@ -0,0 +1,21 @@
+CREATE TABLE
+  public.payment_transactions (
+    id integer NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    orders_id integer NOT NULL,
+    provider character varying(50) NOT NULL,
+    reference character varying(100) NOT NULL,
+    amount bigint NOT NULL DEFAULT 0,
+    status character varying(20) NOT NULL,
+    redirect_url text NULL,
+    created_at timestamp without time zone NOT NULL DEFAULT now(),
+    updated_at timestamp without time zone NULL
+  );
+
+ALTER TABLE
+  public.payment_transactions
+ADD
+  CONSTRAINT payment_transactions_pkey PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX payment_transactions_provider_reference_key ON public.payment_transactions (provider, reference);
+
+CREATE INDEX payment_transactions_orders_id_idx ON public.payment_transactions (orders_id);
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE
+  public.seats
+DROP
+  COLUMN IF EXISTS seat_class;
+
+DROP TABLE IF EXISTS seat_classes;
\ No newline at end of file
@@ -0,0 +1,21 @@
+CREATE TABLE
+  public.seat_classes (
+    code character varying(20) NOT NULL,
+    name character varying(50) NOT NULL,
+    surcharge integer NOT NULL DEFAULT 0
+  );
+
+ALTER TABLE
+  public.seat_classes
+ADD
+  CONSTRAINT seat_classes_pkey PRIMARY KEY (code);
+
+INSERT INTO
+  public.seat_classes (code, name, surcharge)
+VALUES
+  ('regular', 'Regular', 0);
+
+ALTER TABLE
+  public.seats
+ADD
+  COLUMN seat_class character varying(20) NOT NULL DEFAULT 'regular';
\ No newline at end of file
@@ -0,0 +1,32 @@
+ALTER TABLE
+  public.order_seats
+DROP
+  COLUMN IF EXISTS price;
+
+ALTER TABLE
+  public.orders
+DROP
+  COLUMN IF EXISTS subtotal,
+DROP
+  COLUMN IF EXISTS fees,
+DROP
+  COLUMN IF EXISTS discount,
+DROP
+  COLUMN IF EXISTS total;
+
+ALTER TABLE
+  public.times
+DROP
+  COLUMN IF EXISTS surcharge;
+
+ALTER TABLE
+  public.schedules
+DROP
+  COLUMN IF EXISTS base_price;
+
+ALTER TABLE
+  public.cinemas
+DROP
+  COLUMN IF EXISTS base_price,
+DROP
+  COLUMN IF EXISTS weekend_surcharge;
\ No newline at end of file
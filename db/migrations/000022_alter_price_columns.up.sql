@@ -0,0 +1,32 @@
+ALTER TABLE
+  public.cinemas
+ADD
+  COLUMN base_price integer NOT NULL DEFAULT 35000,
+ADD
+  COLUMN weekend_surcharge integer NOT NULL DEFAULT 10000;
+
+ALTER TABLE
+  public.schedules
+ADD
+  COLUMN base_price integer NULL;
+
+ALTER TABLE
+  public.times
+ADD
+  COLUMN surcharge integer NOT NULL DEFAULT 0;
+
+ALTER TABLE
+  public.orders
+ADD
+  COLUMN subtotal integer NOT NULL DEFAULT 0,
+ADD
+  COLUMN fees integer NOT NULL DEFAULT 0,
+ADD
+  COLUMN discount integer NOT NULL DEFAULT 0,
+ADD
+  COLUMN total integer NOT NULL DEFAULT 0;
+
+ALTER TABLE
+  public.order_seats
+ADD
+  COLUMN price integer NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1,14 @@
+ALTER TABLE
+  public.order_seats
+DROP
+  COLUMN IF EXISTS admitted_by;
+
+ALTER TABLE
+  public.order_seats
+DROP
+  COLUMN IF EXISTS admitted_at;
+
+ALTER TABLE
+  public.users
+DROP
+  COLUMN IF EXISTS cinemas_id;
\ No newline at end of file
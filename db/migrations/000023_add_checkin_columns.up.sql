@@ -0,0 +1,19 @@
+ALTER TABLE
+  public.users
+ADD
+  COLUMN cinemas_id integer NULL;
+
+ALTER TABLE
+  public.users
+ADD
+  CONSTRAINT users_cinemas_id_fkey FOREIGN KEY (cinemas_id) REFERENCES public.cinemas (id);
+
+ALTER TABLE
+  public.order_seats
+ADD
+  COLUMN admitted_at timestamp without time zone NULL;
+
+ALTER TABLE
+  public.order_seats
+ADD
+  COLUMN admitted_by uuid NULL;
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE
+  public.order_refunds (
+    id integer NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    orders_id integer NOT NULL,
+    provider character varying(50) NULL,
+    charge_reference character varying(100) NULL,
+    reference character varying(100) NULL,
+    amount bigint NOT NULL DEFAULT 0,
+    seat_codes text[] NOT NULL,
+    reason text NULL,
+    status character varying(20) NOT NULL,
+    requested_by uuid NOT NULL,
+    created_at timestamp without time zone NOT NULL DEFAULT now(),
+    updated_at timestamp without time zone NULL
+  );
+
+ALTER TABLE
+  public.order_refunds
+ADD
+  CONSTRAINT order_refunds_pkey PRIMARY KEY (id);
+
+CREATE INDEX order_refunds_orders_id_idx ON public.order_refunds (orders_id);
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS orders_reference_key;
+
+ALTER TABLE
+  public.orders
+DROP
+  COLUMN IF EXISTS reference;
\ No newline at end of file
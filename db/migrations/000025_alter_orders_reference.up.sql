@@ -0,0 +1,18 @@
+ALTER TABLE
+  public.orders
+ADD
+  COLUMN reference character varying(16) NULL;
+
+UPDATE
+  public.orders
+SET
+  reference = upper(substr(md5(random()::text || id::text), 1, 10));
+
+ALTER TABLE
+  public.orders
+ALTER COLUMN
+  reference
+SET
+  NOT NULL;
+
+CREATE UNIQUE INDEX orders_reference_key ON public.orders (reference);
\ No newline at end of file
@@ -0,0 +1,17 @@
+ALTER TABLE
+  public.schedules
+DROP
+  COLUMN IF EXISTS auditoriums_id;
+
+DROP INDEX IF EXISTS seats_auditorium_seat_code_key;
+
+ALTER TABLE
+  public.seats
+DROP
+  COLUMN IF EXISTS column_number,
+DROP
+  COLUMN IF EXISTS row_number,
+DROP
+  COLUMN IF EXISTS auditoriums_id;
+
+DROP TABLE IF EXISTS auditoriums;
\ No newline at end of file
@@ -0,0 +1,141 @@
+CREATE TABLE
+  public.auditoriums (
+    id integer NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    cinemas_id integer NULL,
+    locations_id integer NULL,
+    name character varying(50) NOT NULL,
+    row_count integer NOT NULL DEFAULT 0,
+    column_count integer NOT NULL DEFAULT 0,
+    created_at timestamp without time zone NOT NULL DEFAULT now(),
+    updated_at timestamp without time zone NULL,
+    deleted_at timestamp without time zone NULL
+  );
+
+ALTER TABLE
+  public.auditoriums
+ADD
+  CONSTRAINT auditoriums_pkey PRIMARY KEY (id);
+
+ALTER TABLE
+  public.auditoriums
+ADD
+  CONSTRAINT auditoriums_cinemas_id_fkey FOREIGN KEY (cinemas_id) REFERENCES public.cinemas (id);
+
+ALTER TABLE
+  public.auditoriums
+ADD
+  CONSTRAINT auditoriums_locations_id_fkey FOREIGN KEY (locations_id) REFERENCES public.locations (id);
+
+-- Seats used to be shared by every schedule. They move into one legacy hall that is not
+-- tied to a cinema, and every existing schedule plays there.
+INSERT INTO
+  public.auditoriums (name)
+VALUES
+  ('Legacy');
+
+ALTER TABLE
+  public.seats
+ADD
+  COLUMN auditoriums_id integer NULL,
+ADD
+  COLUMN row_number integer NULL,
+ADD
+  COLUMN column_number integer NULL;
+
+UPDATE
+  public.seats se
+SET
+  auditoriums_id = (
+    SELECT
+      MIN(id)
+    FROM
+      public.auditoriums
+  ),
+  row_number = p.row_number,
+  column_number = p.column_number
+FROM
+  (
+    SELECT
+      id,
+      DENSE_RANK() OVER (
+        ORDER BY
+          COALESCE(substring(seat_code FROM '^[A-Za-z]+'), '')
+      ) AS row_number,
+      COALESCE(
+        substring(seat_code FROM '[0-9]+$')::integer,
+        ROW_NUMBER() OVER (
+          PARTITION BY COALESCE(substring(seat_code FROM '^[A-Za-z]+'), '')
+          ORDER BY
+            id
+        )
+      ) AS column_number
+    FROM
+      public.seats
+  ) p
+WHERE
+  se.id = p.id;
+
+UPDATE
+  public.auditoriums
+SET
+  row_count = (
+    SELECT
+      COALESCE(MAX(row_number), 0)
+    FROM
+      public.seats
+  ),
+  column_count = (
+    SELECT
+      COALESCE(MAX(column_number), 0)
+    FROM
+      public.seats
+  );
+
+ALTER TABLE
+  public.seats
+ALTER COLUMN
+  auditoriums_id
+SET
+  NOT NULL,
+ALTER COLUMN
+  row_number
+SET
+  NOT NULL,
+ALTER COLUMN
+  column_number
+SET
+  NOT NULL;
+
+ALTER TABLE
+  public.seats
+ADD
+  CONSTRAINT seats_auditoriums_id_fkey FOREIGN KEY (auditoriums_id) REFERENCES public.auditoriums (id);
+
+CREATE UNIQUE INDEX seats_auditorium_seat_code_key ON public.seats (auditoriums_id, seat_code);
+
+ALTER TABLE
+  public.schedules
+ADD
+  COLUMN auditoriums_id integer NULL;
+
+UPDATE
+  public.schedules
+SET
+  auditoriums_id = (
+    SELECT
+      MIN(id)
+    FROM
+      public.auditoriums
+  );
+
+ALTER TABLE
+  public.schedules
+ALTER COLUMN
+  auditoriums_id
+SET
+  NOT NULL;
+
+ALTER TABLE
+  public.schedules
+ADD
+  CONSTRAINT schedules_auditoriums_id_fkey FOREIGN KEY (auditoriums_id) REFERENCES public.auditoriums (id);
\ No newline at end of file
@@ -0,0 +1,11 @@
+UPDATE
+  public.seats
+SET
+  seat_class = 'regular'
+WHERE
+  seat_class IN ('premium', 'couple', 'wheelchair', 'companion');
+
+DELETE FROM
+  public.seat_classes
+WHERE
+  code IN ('premium', 'couple', 'wheelchair', 'companion');
\ No newline at end of file
@@ -0,0 +1,8 @@
+INSERT INTO
+  public.seat_classes (code, name, surcharge)
+VALUES
+  ('premium', 'Premium', 15000),
+  ('couple', 'Couple / Sweetbox', 25000),
+  ('wheelchair', 'Wheelchair Space', 0),
+  ('companion', 'Companion', 0)
+ON CONFLICT (code) DO NOTHING;
\ No newline at end of file
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS point_ledger;
+
+ALTER TABLE
+  public.orders
+DROP
+  COLUMN IF EXISTS points_earned,
+DROP
+  COLUMN IF EXISTS points_redeemed;
+
+ALTER TABLE
+  public.profile
+ALTER COLUMN
+  point DROP NOT NULL;
\ No newline at end of file
@@ -0,0 +1,63 @@
+UPDATE
+  public.profile
+SET
+  point = 0
+WHERE
+  point IS NULL;
+
+ALTER TABLE
+  public.profile
+ALTER COLUMN
+  point
+SET
+  NOT NULL;
+
+ALTER TABLE
+  public.orders
+ADD
+  COLUMN points_redeemed integer NOT NULL DEFAULT 0,
+ADD
+  COLUMN points_earned integer NOT NULL DEFAULT 0;
+
+CREATE TABLE
+  public.point_ledger (
+    id integer NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    users_id uuid NOT NULL,
+    orders_id integer NULL,
+    kind character varying(20) NOT NULL,
+    points integer NOT NULL,
+    balance integer NOT NULL,
+    note text NULL,
+    created_at timestamp without time zone NOT NULL DEFAULT now()
+  );
+
+ALTER TABLE
+  public.point_ledger
+ADD
+  CONSTRAINT point_ledger_pkey PRIMARY KEY (id);
+
+ALTER TABLE
+  public.point_ledger
+ADD
+  CONSTRAINT point_ledger_users_id_fkey FOREIGN KEY (users_id) REFERENCES public.users (id);
+
+ALTER TABLE
+  public.point_ledger
+ADD
+  CONSTRAINT point_ledger_orders_id_fkey FOREIGN KEY (orders_id) REFERENCES public.orders (id);
+
+CREATE INDEX point_ledger_users_id_idx ON public.point_ledger (users_id, created_at);
+
+-- Opening balances so the ledger adds up to the points users already have.
+INSERT INTO
+  public.point_ledger (users_id, kind, points, balance, note)
+SELECT
+  user_id,
+  'adjust',
+  point,
+  point,
+  'Opening balance'
+FROM
+  public.profile
+WHERE
+  point <> 0;
\ No newline at end of file
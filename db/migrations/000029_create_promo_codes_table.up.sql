@@ -0,0 +1,70 @@
+CREATE TABLE
+  public.promo_codes (
+    id integer NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    code character varying(32) NOT NULL,
+    description text NULL,
+    discount_type character varying(10) NOT NULL,
+    discount_value integer NOT NULL,
+    max_discount integer NULL,
+    min_tickets integer NOT NULL DEFAULT 1,
+    starts_at timestamp without time zone NULL,
+    ends_at timestamp without time zone NULL,
+    movie_ids integer[] NOT NULL DEFAULT '{}',
+    cinema_ids integer[] NOT NULL DEFAULT '{}',
+    payment_ids integer[] NOT NULL DEFAULT '{}',
+    usage_limit integer NULL,
+    per_user_limit integer NULL,
+    used_count integer NOT NULL DEFAULT 0,
+    created_at timestamp without time zone NOT NULL DEFAULT now(),
+    updated_at timestamp without time zone NULL,
+    deleted_at timestamp without time zone NULL
+  );
+
+ALTER TABLE
+  public.promo_codes
+ADD
+  CONSTRAINT promo_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE
+  public.promo_codes
+ADD
+  CONSTRAINT promo_codes_discount_type_check CHECK (discount_type IN ('percent', 'fixed'));
+
+CREATE UNIQUE INDEX promo_codes_code_key ON public.promo_codes (code)
+WHERE
+  deleted_at IS NULL;
+
+CREATE TABLE
+  public.promo_redemptions (
+    id integer NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    promo_codes_id integer NOT NULL,
+    orders_id integer NOT NULL,
+    users_id uuid NOT NULL,
+    discount integer NOT NULL,
+    created_at timestamp without time zone NOT NULL DEFAULT now(),
+    released_at timestamp without time zone NULL
+  );
+
+ALTER TABLE
+  public.promo_redemptions
+ADD
+  CONSTRAINT promo_redemptions_pkey PRIMARY KEY (id);
+
+ALTER TABLE
+  public.promo_redemptions
+ADD
+  CONSTRAINT promo_redemptions_promo_codes_id_fkey FOREIGN KEY (promo_codes_id) REFERENCES public.promo_codes (id);
+
+ALTER TABLE
+  public.promo_redemptions
+ADD
+  CONSTRAINT promo_redemptions_orders_id_fkey FOREIGN KEY (orders_id) REFERENCES public.orders (id);
+
+ALTER TABLE
+  public.promo_redemptions
+ADD
+  CONSTRAINT promo_redemptions_users_id_fkey FOREIGN KEY (users_id) REFERENCES public.users (id);
+
+CREATE UNIQUE INDEX promo_redemptions_orders_id_key ON public.promo_redemptions (orders_id);
+
+CREATE INDEX promo_redemptions_promo_user_idx ON public.promo_redemptions (promo_codes_id, users_id);
\ No newline at end of file
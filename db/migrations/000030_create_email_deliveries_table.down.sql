@@ -0,0 +1 @@
+DROP TABLE IF EXISTS email_deliveries;
\ No newline at end of file
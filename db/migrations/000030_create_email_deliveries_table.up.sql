@@ -0,0 +1,29 @@
+CREATE TABLE
+  public.email_deliveries (
+    id integer NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    orders_id integer NOT NULL,
+    kind character varying(20) NOT NULL,
+    recipient character varying(255) NOT NULL,
+    subject text NULL,
+    status character varying(20) NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text NULL,
+    next_attempt_at timestamp without time zone NOT NULL DEFAULT now(),
+    sent_at timestamp without time zone NULL,
+    created_at timestamp without time zone NOT NULL DEFAULT now(),
+    updated_at timestamp without time zone NULL
+  );
+
+ALTER TABLE
+  public.email_deliveries
+ADD
+  CONSTRAINT email_deliveries_pkey PRIMARY KEY (id);
+
+ALTER TABLE
+  public.email_deliveries
+ADD
+  CONSTRAINT email_deliveries_orders_id_fkey FOREIGN KEY (orders_id) REFERENCES public.orders (id);
+
+CREATE UNIQUE INDEX email_deliveries_order_kind_key ON public.email_deliveries (orders_id, kind);
+
+CREATE INDEX email_deliveries_due_idx ON public.email_deliveries (status, next_attempt_at);
\ No newline at end of file
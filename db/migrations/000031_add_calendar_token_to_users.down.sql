@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS users_calendar_token_idx;
+
+ALTER TABLE
+  public.users
+DROP
+  COLUMN IF EXISTS calendar_token;
\ No newline at end of file
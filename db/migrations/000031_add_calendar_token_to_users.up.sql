@@ -0,0 +1,6 @@
+ALTER TABLE
+  public.users
+ADD
+  COLUMN calendar_token character varying(64) NULL;
+
+CREATE UNIQUE INDEX users_calendar_token_idx ON public.users (calendar_token);
\ No newline at end of file
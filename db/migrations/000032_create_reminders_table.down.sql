@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS reminders;
+
+ALTER TABLE
+  public.profile
+DROP
+  COLUMN IF EXISTS reminder_hours,
+DROP
+  COLUMN IF EXISTS notify_sms,
+DROP
+  COLUMN IF EXISTS notify_push,
+DROP
+  COLUMN IF EXISTS notify_email;
\ No newline at end of file
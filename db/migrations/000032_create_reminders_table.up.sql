@@ -0,0 +1,38 @@
+ALTER TABLE
+  public.profile
+ADD
+  COLUMN notify_email boolean NOT NULL DEFAULT true,
+ADD
+  COLUMN notify_push boolean NOT NULL DEFAULT false,
+ADD
+  COLUMN notify_sms boolean NOT NULL DEFAULT false,
+ADD
+  COLUMN reminder_hours integer NOT NULL DEFAULT 3;
+
+CREATE TABLE
+  public.reminders (
+    id integer NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    orders_id integer NOT NULL,
+    channel character varying(20) NOT NULL,
+    status character varying(20) NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text NULL,
+    send_at timestamp without time zone NOT NULL,
+    sent_at timestamp without time zone NULL,
+    created_at timestamp without time zone NOT NULL DEFAULT now(),
+    updated_at timestamp without time zone NULL
+  );
+
+ALTER TABLE
+  public.reminders
+ADD
+  CONSTRAINT reminders_pkey PRIMARY KEY (id);
+
+ALTER TABLE
+  public.reminders
+ADD
+  CONSTRAINT reminders_orders_id_fkey FOREIGN KEY (orders_id) REFERENCES public.orders (id);
+
+CREATE UNIQUE INDEX reminders_order_channel_key ON public.reminders (orders_id, channel);
+
+CREATE INDEX reminders_due_idx ON public.reminders (status, send_at);
\ No newline at end of file
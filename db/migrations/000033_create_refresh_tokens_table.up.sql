@@ -0,0 +1,25 @@
+CREATE TABLE
+  public.refresh_tokens (
+    id integer NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    users_id uuid NOT NULL,
+    family_id uuid NOT NULL,
+    token_hash character(64) NOT NULL,
+    expires_at timestamp without time zone NOT NULL,
+    used_at timestamp without time zone NULL,
+    revoked_at timestamp without time zone NULL,
+    created_at timestamp without time zone NOT NULL DEFAULT now()
+  );
+
+ALTER TABLE
+  public.refresh_tokens
+ADD
+  CONSTRAINT refresh_tokens_pkey PRIMARY KEY (id);
+
+ALTER TABLE
+  public.refresh_tokens
+ADD
+  CONSTRAINT refresh_tokens_users_id_fkey FOREIGN KEY (users_id) REFERENCES public.users (id);
+
+CREATE UNIQUE INDEX refresh_tokens_token_hash_key ON public.refresh_tokens (token_hash);
+
+CREATE INDEX refresh_tokens_family_id_idx ON public.refresh_tokens (family_id);
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE
+  public.users
+DROP
+  COLUMN IF EXISTS verified_at;
\ No newline at end of file
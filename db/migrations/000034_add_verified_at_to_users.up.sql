@@ -0,0 +1,10 @@
+ALTER TABLE
+  public.users
+ADD
+  COLUMN verified_at timestamp without time zone NULL;
+
+-- Accounts created before verification existed are treated as verified.
+UPDATE
+  public.users
+SET
+  verified_at = created_at;
\ No newline at end of file
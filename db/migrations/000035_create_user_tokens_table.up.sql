@@ -0,0 +1,24 @@
+CREATE TABLE
+  public.user_tokens (
+    id integer NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    users_id uuid NOT NULL,
+    purpose character varying(32) NOT NULL,
+    token_hash character(64) NOT NULL,
+    expires_at timestamp without time zone NOT NULL,
+    used_at timestamp without time zone NULL,
+    created_at timestamp without time zone NOT NULL DEFAULT now()
+  );
+
+ALTER TABLE
+  public.user_tokens
+ADD
+  CONSTRAINT user_tokens_pkey PRIMARY KEY (id);
+
+ALTER TABLE
+  public.user_tokens
+ADD
+  CONSTRAINT user_tokens_users_id_fkey FOREIGN KEY (users_id) REFERENCES public.users (id);
+
+CREATE UNIQUE INDEX user_tokens_token_hash_key ON public.user_tokens (token_hash);
+
+CREATE INDEX user_tokens_users_id_purpose_idx ON public.user_tokens (users_id, purpose, created_at);
\ No newline at end of file
@@ -15,21 +15,61 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/movies": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Public keys access tokens are signed with, by kid. Keys being rotated out stay listed until they retire. The set is returned as-is, not wrapped in a response envelope.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "JSON Web Key Set",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pkg.JWKS"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auditoriums": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all movies",
+                "description": "List halls, optionally filtered by cinema and location",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Movies"
+                    "Admin - Auditoriums"
+                ],
+                "summary": "List auditoriums",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cinema ID",
+                        "name": "cinema_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get list of movies",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -37,6 +77,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -51,92 +97,26 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add a new movie with genres, casts, schedules, poster \u0026 backdrop",
+                "description": "Add a hall to a cinema at a location. Without seats every cell of the grid becomes a regular seat.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Movies"
+                    "Admin - Auditoriums"
                 ],
-                "summary": "Create new movie",
+                "summary": "Create auditorium",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Movie Title",
-                        "name": "title",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Movie Overview",
-                        "name": "overview",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Director Name",
-                        "name": "director_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Duration in minutes",
-                        "name": "duration",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Release date in format YYYY-MM-DD",
-                        "name": "release_date",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Popularity",
-                        "name": "popularity",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Poster image",
-                        "name": "poster",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Backdrop image",
-                        "name": "backdrop",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "integer"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "Genre IDs (contoh: [1,2])",
-                        "name": "genres",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "integer"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "Cast IDs (contoh: [3,5,7])",
-                        "name": "casts",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Schedules JSON [{cinema_id, location_id, date, time_ids}]",
-                        "name": "schedules",
-                        "in": "formData"
+                        "description": "Hall and optional seat map",
+                        "name": "auditorium",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateAuditoriumRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -161,25 +141,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/movies/{id}": {
+        "/admin/auditoriums/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a movie by ID",
+                "description": "Get a hall with its seat map",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Movies"
+                    "Admin - Auditoriums"
                 ],
-                "summary": "Get movie detail",
+                "summary": "Get auditorium",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie ID",
+                        "description": "Auditorium ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -192,11 +172,23 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/dtos.Response"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
                     }
                 }
             },
@@ -206,18 +198,18 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Soft delete a movie by ID",
+                "description": "Soft delete a hall without upcoming schedules",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Movies"
+                    "Admin - Auditoriums"
                 ],
-                "summary": "Delete movie",
+                "summary": "Delete auditorium",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie ID",
+                        "description": "Auditorium ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -230,6 +222,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -244,92 +254,32 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update movie data including genres, casts, and images",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Movies"
+                    "Admin - Auditoriums"
                 ],
-                "summary": "Update movie",
+                "summary": "Rename auditorium",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie ID",
+                        "description": "Auditorium ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Movie Title",
-                        "name": "title",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Movie Overview",
-                        "name": "overview",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Director Name",
-                        "name": "director_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Duration in minutes",
-                        "name": "duration",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Release date in format YYYY-MM-DD",
-                        "name": "release_date",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Popularity",
-                        "name": "popularity",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Poster image",
-                        "name": "poster",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Backdrop image",
-                        "name": "backdrop",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "integer"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Genre IDs (contoh: [1,2,3])",
-                        "name": "genres",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "integer"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Cast IDs (contoh: [4,6])",
-                        "name": "casts",
-                        "in": "formData"
+                        "description": "New name",
+                        "name": "auditorium",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateAuditoriumRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -360,9 +310,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login": {
-            "post": {
-                "description": "Authenticate user with email and password",
+        "/admin/auditoriums/{id}/layout": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the grid size and seats of a hall. Cells without a seat are gaps. Halls with sold seats cannot be changed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -370,17 +325,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin - Auditoriums"
                 ],
-                "summary": "User login",
+                "summary": "Replace seat map",
                 "parameters": [
                     {
-                        "description": "User login credentials",
-                        "name": "body",
+                        "type": "integer",
+                        "description": "Auditorium ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Seat map",
+                        "name": "layout",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dtos.UserRequest"
+                            "$ref": "#/definitions/dtos.AuditoriumLayoutRequest"
                         }
                     }
                 ],
@@ -391,88 +353,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Register a new user with email and password",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "User registration",
-                "parameters": [
-                    {
-                        "description": "User registration request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dtos.UserRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
-                    }
-                }
-            }
-        },
-        "/movies": {
-            "get": {
-                "description": "Retrieve a paginated list of movies with optional search and genre filter",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Movies"
-                ],
-                "summary": "Get all movies",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search by title",
-                        "name": "search",
-                        "in": "query"
                     },
-                    {
-                        "type": "integer",
-                        "description": "Filter by genre ID",
-                        "name": "genre",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/dtos.Response"
                         }
@@ -480,22 +374,27 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
             }
         },
-        "/movies/genres": {
+        "/admin/movies": {
             "get": {
-                "description": "Retrieve list of all available genres",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all movies",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Movies"
+                    "Admin - Movies"
                 ],
-                "summary": "Get all genres",
+                "summary": "Get list of movies",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -506,33 +405,114 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
-            }
-        },
-        "/movies/popular": {
-            "get": {
-                "description": "Retrieve a paginated list of popular movies",
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a new movie with genres, casts, schedules, poster \u0026 backdrop",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Movies"
+                    "Admin - Movies"
                 ],
-                "summary": "Get popular movies",
+                "summary": "Create new movie",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Movie Title",
+                        "name": "title",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Movie Overview",
+                        "name": "overview",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Director Name",
+                        "name": "director_name",
+                        "in": "formData"
+                    },
                     {
                         "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Duration in minutes",
+                        "name": "duration",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Release date in format YYYY-MM-DD",
+                        "name": "release_date",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Popularity",
+                        "name": "popularity",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Poster image",
+                        "name": "poster",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Backdrop image",
+                        "name": "backdrop",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Genre IDs (contoh: [1,2])",
+                        "name": "genres",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Cast IDs (contoh: [3,5,7])",
+                        "name": "casts",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedules JSON [{cinema_id, location_id, auditorium_id, date, time_ids}]",
+                        "name": "schedules",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/dtos.Response"
                         }
@@ -540,28 +520,34 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
             }
         },
-        "/movies/upcoming": {
+        "/admin/movies/{id}": {
             "get": {
-                "description": "Retrieve a paginated list of upcoming movies",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a movie by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Movies"
+                    "Admin - Movies"
                 ],
-                "summary": "Get upcoming movies",
+                "summary": "Get movie detail",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -571,25 +557,28 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
-            }
-        },
-        "/movies/{id}": {
-            "get": {
-                "description": "Retrieve detailed information about a movie by ID",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft delete a movie by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Movies"
+                    "Admin - Movies"
                 ],
-                "summary": "Get movie detail",
+                "summary": "Delete movie",
                 "parameters": [
                     {
                         "type": "integer",
@@ -609,44 +598,120 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
-            }
-        },
-        "/orders": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new order and save to database",
+                "description": "Update movie data including genres, casts, and images",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin - Movies"
                 ],
-                "summary": "Create a new order",
+                "summary": "Update movie",
                 "parameters": [
                     {
-                        "description": "Order Data",
-                        "name": "order",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dtos.CreateOrderRequest"
-                        }
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Movie Title",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Movie Overview",
+                        "name": "overview",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Director Name",
+                        "name": "director_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Duration in minutes",
+                        "name": "duration",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Release date in format YYYY-MM-DD",
+                        "name": "release_date",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Popularity",
+                        "name": "popularity",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Poster image",
+                        "name": "poster",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Backdrop image",
+                        "name": "backdrop",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Genre IDs (contoh: [1,2,3])",
+                        "name": "genres",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Cast IDs (contoh: [4,6])",
+                        "name": "casts",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/dtos.Response"
                         }
@@ -654,27 +719,54 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
             }
         },
-        "/orders/cinemas": {
-            "get": {
+        "/admin/orders/{id}/cancel": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve list of available cinemas",
+                "description": "Cancel any order or some of its seats regardless of the cancellation cutoff",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin - Orders"
+                ],
+                "summary": "Cancel an order (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason and seats to cancel, all when empty",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.AdminCancelOrderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Repeat-safe request key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
                 ],
-                "summary": "Get all cinemas",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -682,6 +774,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -691,21 +801,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/history": {
+        "/admin/orders/{id}/emails": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve order history for current user",
+                "description": "Get the delivery log of the emails sent for an order",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin - Orders"
+                ],
+                "summary": "List order emails",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get order history",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -713,33 +832,60 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
             }
         },
-        "/orders/locations": {
-            "get": {
+        "/admin/orders/{id}/emails/resend": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve list of available locations",
+                "description": "Queue the e-ticket email of an order again, to the order's current email address",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin - Orders"
+                ],
+                "summary": "Resend e-ticket email",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all locations",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/dtos.Response"
                         }
@@ -747,27 +893,36 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
             }
         },
-        "/orders/payments": {
+        "/admin/orders/{id}/reminders": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve list of available payment methods",
+                "description": "Get the showtime reminders scheduled for an order and how their delivery went",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin - Orders"
+                ],
+                "summary": "List order reminders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all payment methods",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -775,37 +930,55 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
             }
         },
-        "/orders/schedules": {
-            "get": {
+        "/admin/orders/{id}/status": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all schedules for a movie",
+                "description": "Move an order to another status following the order lifecycle",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin - Orders"
                 ],
-                "summary": "Get schedules by movie ID",
+                "summary": "Update order status",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie ID",
-                        "name": "movie_id",
-                        "in": "query",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateOrderStatusRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -815,6 +988,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -824,30 +1009,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/seats": {
+        "/admin/promos": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve available seats for a schedule",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
-                ],
-                "summary": "Get available seats",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Schedule ID",
-                        "name": "schedule_id",
-                        "in": "query",
-                        "required": true
-                    }
+                    "Admin - Promos"
                 ],
+                "summary": "List promo codes",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -858,27 +1033,91 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Percentage or fixed discount on the tickets of an order, optionally limited to movies, cinemas, payment methods, dates and number of uses",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Promos"
+                ],
+                "summary": "Create promo code",
+                "parameters": [
+                    {
+                        "description": "Promo code",
+                        "name": "promo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreatePromoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
             }
         },
-        "/orders/times": {
+        "/admin/promos/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve list of available movie times",
+                "description": "Get a promo code with its restrictions and usage count",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin - Promos"
+                ],
+                "summary": "Get promo code",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all times",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -886,37 +1125,59 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
-            }
-        },
-        "/orders/{id}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve detail of a transaction",
+                "description": "Replace the discount and restrictions of a promo code. The code and its usage count are kept.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin - Promos"
                 ],
-                "summary": "Get transaction detail",
+                "summary": "Update promo code",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
+                        "description": "Promo ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Promo rules",
+                        "name": "promo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.PromoRules"
+                        }
                     }
                 ],
                 "responses": {
@@ -926,30 +1187,49 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
-            }
-        },
-        "/profile": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve logged in user profile",
+                "description": "Soft delete a promo code. Orders that already used it keep their discount.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Admin - Promos"
+                ],
+                "summary": "Delete promo code",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get user profile",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -957,21 +1237,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/users/{id}/staff": {
             "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update first name, last name, and phone number",
+                "description": "Give a user the staff role at a cinema so they can check in tickets there",
                 "consumes": [
                     "application/json"
                 ],
@@ -979,17 +1273,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Admin - Users"
                 ],
-                "summary": "Update user profile",
+                "summary": "Assign staff",
                 "parameters": [
                     {
-                        "description": "Profile update request",
-                        "name": "body",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cinema the staff member works at",
+                        "name": "staff",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dtos.ProfileRequest"
+                            "$ref": "#/definitions/dtos.AssignStaffRequest"
                         }
                     }
                 ],
@@ -1000,40 +1301,95 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dtos.ErrResponse"
+                            "$ref": "#/definitions/dtos.Response"
                         }
                     }
                 }
             }
         },
-        "/profile/change-avatar": {
-            "patch": {
-                "security": [
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Mail a link for choosing a new password. The response is the same whether or not an account has the email, and requests beyond RESET_REQUEST_INTERVAL and RESET_REQUESTS_PER_HOUR are dropped silently.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Forgot password",
+                "parameters": [
                     {
-                        "BearerAuth": []
+                        "description": "Email of the account",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ForgotPasswordRequest"
+                        }
                     }
                 ],
-                "description": "Upload a new avatar image for user profile",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate user with email and password. With VERIFY_REQUIRED_FOR_LOGIN set, users who have not verified their email get 403. Repeated failures slow down further attempts on the account and eventually lock it, and too many from one address block it; both answer 429 with Retry-After.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Auth"
                 ],
-                "summary": "Change user avatar",
+                "summary": "User login",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Avatar image",
-                        "name": "avatar",
-                        "in": "formData",
-                        "required": true
+                        "description": "User login credentials",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UserRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1043,6 +1399,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1052,14 +1426,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile/change-password": {
-            "patch": {
+        "/auth/logout": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update old password to new password",
+                "description": "Revoke the access token used for this request and, when given, the session of the refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -1067,17 +1441,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Auth"
                 ],
-                "summary": "Change user password",
+                "summary": "Logout",
                 "parameters": [
                     {
-                        "description": "Change password request",
+                        "description": "Refresh token to revoke",
                         "name": "body",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dtos.ChangePasswordRequest"
+                            "$ref": "#/definitions/dtos.LogoutRequest"
                         }
                     }
                 ],
@@ -1088,17 +1461,2164 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/dtos.ErrResponse"
                         }
-                    }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token and a new refresh token. Each refresh token works once; presenting a used one ends the whole session.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token from login or the previous refresh",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Register a new user with email and password. A link to verify the email is mailed to it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User registration",
+                "parameters": [
+                    {
+                        "description": "User registration request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/reset-password": {
+            "post": {
+                "description": "Set a new password with the token from the reset email. Each token works once, and using it signs the user out of every session and lifts a login lockout.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/unlock": {
+            "get": {
+                "description": "Lift a login lockout with the token from the email sent when the account was locked. Each token works once.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Unlock account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unlock token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify": {
+            "get": {
+                "description": "Confirm the email of an account with the token from the link mailed at registration. Each token works once.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Verify email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Verification token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify/resend": {
+            "post": {
+                "description": "Mail a new verification link. The response is the same whether or not the account exists or is already verified, and requests beyond VERIFY_RESEND_INTERVAL and VERIFY_RESEND_PER_HOUR are dropped silently.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Resend verification email",
+                "parameters": [
+                    {
+                        "description": "Email of the account",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ResendVerificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/calendar/{token}/bookings.ics": {
+            "get": {
+                "description": "iCalendar feed of the paid bookings of the user the token belongs to. The token in the path is the only credential.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Calendar feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed token from /profile/calendar",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/checkin": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Verify a scanned ticket and admit its seats. Each seat is admitted once; replays are rejected with a reason.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Check-in"
+                ],
+                "summary": "Check in a ticket",
+                "parameters": [
+                    {
+                        "description": "Scanned ticket and optional seats to admit",
+                        "name": "checkin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CheckinRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/checkin/schedules/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Seats and orders sold versus admitted for a schedule",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Check-in"
+                ],
+                "summary": "Get admission summary",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies": {
+            "get": {
+                "description": "Retrieve a paginated list of movies with optional search and genre filter",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movies"
+                ],
+                "summary": "Get all movies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by title",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by genre ID",
+                        "name": "genre",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/genres": {
+            "get": {
+                "description": "Retrieve list of all available genres",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movies"
+                ],
+                "summary": "Get all genres",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/popular": {
+            "get": {
+                "description": "Retrieve a paginated list of popular movies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movies"
+                ],
+                "summary": "Get popular movies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/upcoming": {
+            "get": {
+                "description": "Retrieve a paginated list of upcoming movies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movies"
+                ],
+                "summary": "Get upcoming movies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}": {
+            "get": {
+                "description": "Retrieve detailed information about a movie by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movies"
+                ],
+                "summary": "Get movie detail",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a pending order for held seats and start its payment. The response carries the payment redirect. Couple seats must be booked in pairs, companion seats with the wheelchair space beside them, and no single seat may be left empty between booked ones. Points and a promo code can be redeemed as a discount on the tickets. With VERIFY_REQUIRED_FOR_ORDERS set, users who have not verified their email get 403.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Create a new order",
+                "parameters": [
+                    {
+                        "description": "Order Data",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateOrderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Repeat-safe request key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/cinemas": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve list of available cinemas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get all cinemas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve order history for current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get order history",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/holds": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Temporarily reserve seats of a schedule for the current user before checkout",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Hold seats",
+                "parameters": [
+                    {
+                        "description": "Seats to hold",
+                        "name": "hold",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SeatHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Release seats held by the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Release seat hold",
+                "parameters": [
+                    {
+                        "description": "Held seats",
+                        "name": "hold",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SeatHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Refresh the expiry of seats currently held by the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Extend seat hold",
+                "parameters": [
+                    {
+                        "description": "Held seats",
+                        "name": "hold",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SeatHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/locations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve list of available locations",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get all locations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/payments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve list of available payment methods",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get all payment methods",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/promo/validate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Check a promo code against the selected seats without using it and return the discounted price",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Validate a promo code",
+                "parameters": [
+                    {
+                        "description": "Promo code and seats",
+                        "name": "promo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ValidatePromoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/quote": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Price the selected seats of a schedule, including surcharges and fees",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get a price quote",
+                "parameters": [
+                    {
+                        "description": "Seats to price",
+                        "name": "quote",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.PriceQuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/schedules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all schedules for a movie",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get schedules by movie ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "movie_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/seats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the seat map of a schedule's auditorium with the status of every seat",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get available seats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "schedule_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/times": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve list of available movie times",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get all times",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{reference}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve detail of one of the current user's orders",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get transaction detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "7KQ2M9XD4R",
+                        "description": "Order reference",
+                        "name": "reference",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{reference}/calendar.ics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download an iCalendar event for the showtime of a paid order",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Add booking to calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "7KQ2M9XD4R",
+                        "description": "Order reference",
+                        "name": "reference",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{reference}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel the whole order or some of its seats before the cancellation cutoff. Paid seats are refunded through the payment provider.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Cancel an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "7KQ2M9XD4R",
+                        "description": "Order reference",
+                        "name": "reference",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Seats to cancel, all when empty",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CancelOrderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Repeat-safe request key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{reference}/qrcode": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Render the signed ticket token of a paid order as a QR code image",
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get ticket QR code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "7KQ2M9XD4R",
+                        "description": "Order reference",
+                        "name": "reference",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "png",
+                        "description": "Image format (png or svg)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 8,
+                        "description": "Pixels per module (1-32)",
+                        "name": "scale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{reference}/ticket.pdf": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download a printable ticket of a paid order with the poster, showtime, seats and QR code",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Download ticket PDF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "7KQ2M9XD4R",
+                        "description": "Order reference",
+                        "name": "reference",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/fake/{reference}/pay": {
+            "get": {
+                "description": "Development checkout page of the in-process fake provider. Settles the charge and delivers its webhook.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Complete a fake payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Charge reference",
+                        "name": "reference",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "paid",
+                        "description": "Resulting charge status (paid or failed)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/webhook/{provider}": {
+            "post": {
+                "description": "Receive a signed charge notification from a payment provider and mark the order paid. A payment the order cannot accept is refunded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Payment webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "fake",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve logged in user profile",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get user profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update first name, last name, and phone number",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Update user profile",
+                "parameters": [
+                    {
+                        "description": "Profile update request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/calendar": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Private URL of an iCalendar feed with every booked showtime, for subscribing from a calendar app",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get calendar feed URL",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CalendarFeedResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/calendar/reset": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issue a new feed URL; subscriptions to the old one stop updating",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Reset calendar feed URL",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CalendarFeedResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/change-avatar": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload a new avatar image for user profile",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Change user avatar",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Avatar image",
+                        "name": "avatar",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/change-password": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update old password to new password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Change user password",
+                "parameters": [
+                    {
+                        "description": "Change password request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Channels showtime reminders are sent on and how many hours before the show",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get notification preferences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.NotificationPreferences"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn reminder channels on or off and set how many hours before the show they are sent. Reminders of upcoming bookings are rescheduled to match.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Update notification preferences",
+                "parameters": [
+                    {
+                        "description": "Fields to change",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.NotificationPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.NotificationPreferences"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/points": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Points balance of the logged in user and every change to it, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get points history",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrResponse"
+                        }
+                    }
                 }
             }
         }
     },
     "definitions": {
+        "dtos.AdminCancelOrderRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Screening cancelled"
+                },
+                "seat_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"A2\"]"
+                    ]
+                }
+            }
+        },
+        "dtos.AssignStaffRequest": {
+            "type": "object",
+            "required": [
+                "cinema_id"
+            ],
+            "properties": {
+                "cinema_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dtos.AuditoriumLayoutRequest": {
+            "type": "object",
+            "required": [
+                "columns",
+                "rows",
+                "seats"
+            ],
+            "properties": {
+                "columns": {
+                    "type": "integer",
+                    "maximum": 60,
+                    "minimum": 1,
+                    "example": 14
+                },
+                "rows": {
+                    "type": "integer",
+                    "maximum": 52,
+                    "minimum": 1,
+                    "example": 8
+                },
+                "seats": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.AuditoriumSeatRequest"
+                    }
+                }
+            }
+        },
+        "dtos.AuditoriumSeatRequest": {
+            "type": "object",
+            "required": [
+                "column",
+                "row"
+            ],
+            "properties": {
+                "column": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 3
+                },
+                "row": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "seat_class": {
+                    "type": "string",
+                    "example": "regular"
+                },
+                "seat_code": {
+                    "type": "string",
+                    "example": "A3"
+                }
+            }
+        },
+        "dtos.CalendarFeedResponse": {
+            "type": "object",
+            "properties": {
+                "feed_url": {
+                    "type": "string",
+                    "example": "https://api.tickitz.id/calendar/Zm9vYmFy/bookings.ics"
+                },
+                "webcal_url": {
+                    "type": "string",
+                    "example": "webcal://api.tickitz.id/calendar/Zm9vYmFy/bookings.ics"
+                }
+            }
+        },
+        "dtos.CancelOrderRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Cannot make it"
+                },
+                "seat_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"A2\"]"
+                    ]
+                }
+            }
+        },
         "dtos.ChangePasswordRequest": {
             "type": "object",
             "required": [
@@ -1106,41 +3626,287 @@ const docTemplate = `{
                 "old_password"
             ],
             "properties": {
-                "new_password": {
-                    "type": "string"
-                },
-                "old_password": {
+                "new_password": {
+                    "type": "string"
+                },
+                "old_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CheckinRequest": {
+            "type": "object",
+            "required": [
+                "ticket"
+            ],
+            "properties": {
+                "seat_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"A1\"]"
+                    ]
+                },
+                "ticket": {
+                    "type": "string",
+                    "example": "TKT1.eyJvaWQiOjEyfQ.c2lnbmF0dXJl"
+                }
+            }
+        },
+        "dtos.CreateAuditoriumRequest": {
+            "type": "object",
+            "required": [
+                "cinema_id",
+                "columns",
+                "location_id",
+                "name",
+                "rows"
+            ],
+            "properties": {
+                "cinema_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "columns": {
+                    "type": "integer",
+                    "maximum": 60,
+                    "minimum": 1,
+                    "example": 14
+                },
+                "location_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Studio 1"
+                },
+                "rows": {
+                    "type": "integer",
+                    "maximum": 52,
+                    "minimum": 1,
+                    "example": 8
+                },
+                "seats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.AuditoriumSeatRequest"
+                    }
+                }
+            }
+        },
+        "dtos.CreateOrderRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "fullname",
+                "payment_id",
+                "phone",
+                "schedule_id",
+                "seat_codes"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "farid@example.com"
+                },
+                "fullname": {
+                    "type": "string",
+                    "example": "Farid Darari"
+                },
+                "payment_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+628123456789"
+                },
+                "points": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 100
+                },
+                "promo_code": {
+                    "type": "string",
+                    "example": "HEMAT20"
+                },
+                "schedule_id": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "seat_codes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"A1\"",
+                        "\"A2\"]"
+                    ]
+                }
+            }
+        },
+        "dtos.CreatePromoRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "discount_type",
+                "discount_value"
+            ],
+            "properties": {
+                "cinema_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3
+                    ]
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "HEMAT20"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "20% off for two tickets or more"
+                },
+                "discount_type": {
+                    "type": "string",
+                    "enum": [
+                        "percent",
+                        "fixed"
+                    ],
+                    "example": "percent"
+                },
+                "discount_value": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 20
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2025-12-31T23:59:59Z"
+                },
+                "max_discount": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 50000
+                },
+                "min_tickets": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                },
+                "movie_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "payment_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        2
+                    ]
+                },
+                "per_user_limit": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2025-12-01T00:00:00Z"
+                },
+                "usage_limit": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 500
+                }
+            }
+        },
+        "dtos.ErrResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "internal server error"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "dtos.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@mail.com"
+                }
+            }
+        },
+        "dtos.LogoutRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
                     "type": "string"
                 }
             }
         },
-        "dtos.CreateOrderRequest": {
+        "dtos.NotificationPreferencesRequest": {
             "type": "object",
-            "required": [
-                "email",
-                "fullname",
-                "payment_id",
-                "phone",
-                "schedule_id",
-                "seat_codes"
-            ],
             "properties": {
                 "email": {
-                    "type": "string",
-                    "example": "farid@example.com"
+                    "type": "boolean",
+                    "example": true
                 },
-                "fullname": {
-                    "type": "string",
-                    "example": "Farid Darari"
+                "push": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "payment_id": {
+                "reminder_hours": {
                     "type": "integer",
-                    "example": 2
-                },
-                "phone": {
-                    "type": "string",
-                    "example": "+628123456789"
+                    "maximum": 72,
+                    "minimum": 1,
+                    "example": 3
                 },
+                "sms": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "dtos.PriceQuoteRequest": {
+            "type": "object",
+            "required": [
+                "schedule_id",
+                "seat_codes"
+            ],
+            "properties": {
                 "schedule_id": {
                     "type": "integer",
                     "example": 8
@@ -1158,36 +3924,139 @@ const docTemplate = `{
                 }
             }
         },
-        "dtos.ErrResponse": {
+        "dtos.ProfileRequest": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 500
+                "firstname": {
+                    "type": "string",
+                    "example": "Farid"
                 },
-                "data": {},
-                "message": {
+                "lastname": {
                     "type": "string",
-                    "example": "internal server error"
+                    "example": "Darari"
                 },
-                "success": {
-                    "type": "boolean",
-                    "example": false
+                "phone_number": {
+                    "type": "string"
                 }
             }
         },
-        "dtos.ProfileRequest": {
+        "dtos.PromoRules": {
             "type": "object",
+            "required": [
+                "discount_type",
+                "discount_value"
+            ],
             "properties": {
-                "firstname": {
+                "cinema_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3
+                    ]
+                },
+                "description": {
                     "type": "string",
-                    "example": "Farid"
+                    "example": "20% off for two tickets or more"
                 },
-                "lastname": {
+                "discount_type": {
                     "type": "string",
-                    "example": "Darari"
+                    "enum": [
+                        "percent",
+                        "fixed"
+                    ],
+                    "example": "percent"
                 },
-                "phone_number": {
+                "discount_value": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 20
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2025-12-31T23:59:59Z"
+                },
+                "max_discount": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 50000
+                },
+                "min_tickets": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                },
+                "movie_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "payment_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        2
+                    ]
+                },
+                "per_user_limit": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2025-12-01T00:00:00Z"
+                },
+                "usage_limit": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 500
+                }
+            }
+        },
+        "dtos.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.ResendVerificationRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@mail.com"
+                }
+            }
+        },
+        "dtos.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "example": "Password123"
+                },
+                "token": {
                     "type": "string"
                 }
             }
@@ -1210,6 +4079,67 @@ const docTemplate = `{
                 }
             }
         },
+        "dtos.SeatHoldRequest": {
+            "type": "object",
+            "required": [
+                "schedule_id",
+                "seat_codes"
+            ],
+            "properties": {
+                "schedule_id": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "seat_codes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"A1\"",
+                        "\"A2\"]"
+                    ]
+                }
+            }
+        },
+        "dtos.UpdateAuditoriumRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "IMAX"
+                }
+            }
+        },
+        "dtos.UpdateOrderStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "Paid at the counter"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "paid",
+                        "checked_in",
+                        "expired",
+                        "refunded",
+                        "cancelled"
+                    ],
+                    "example": "paid"
+                }
+            }
+        },
         "dtos.UserRequest": {
             "type": "object",
             "required": [
@@ -1226,6 +4156,121 @@ const docTemplate = `{
                     "example": "Password123"
                 }
             }
+        },
+        "dtos.UserResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.ValidatePromoRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "schedule_id",
+                "seat_codes"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "HEMAT20"
+                },
+                "payment_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "schedule_id": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "seat_codes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"A1\"",
+                        "\"A2\"]"
+                    ]
+                }
+            }
+        },
+        "models.NotificationPreferences": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "boolean"
+                },
+                "push": {
+                    "type": "boolean"
+                },
+                "reminder_hours": {
+                    "type": "integer"
+                },
+                "sms": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "pkg.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "description": "Ed25519",
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "description": "RSA",
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "pkg.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pkg.JWK"
+                    }
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -13,6 +14,7 @@ import (
 	"github.com/Darari17/be-tickitz-full/internal/repositories"
 	"github.com/Darari17/be-tickitz-full/internal/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AdminController struct {
@@ -41,7 +43,7 @@ func NewAdminController(ar *repositories.AdminRepo) *AdminController {
 // @Param backdrop formData file false "Backdrop image"
 // @Param genres formData []int false "Genre IDs (contoh: [1,2])" collectionFormat(multi)
 // @Param casts formData []int false "Cast IDs (contoh: [3,5,7])" collectionFormat(multi)
-// @Param schedules formData string false "Schedules JSON [{cinema_id, location_id, date, time_ids}]"
+// @Param schedules formData string false "Schedules JSON [{cinema_id, location_id, auditorium_id, date, time_ids}]"
 // @Success 201 {object} dtos.Response
 // @Failure 400 {object} dtos.Response
 // @Failure 500 {object} dtos.Response
@@ -162,10 +164,11 @@ func (ac *AdminController) CreateMovie(c *gin.Context) {
 	for _, s := range body.Schedules {
 		date, _ := time.Parse("2006-01-02", s.Date)
 		schedules = append(schedules, map[string]interface{}{
-			"date":        date,
-			"cinema_id":   s.CinemaID,
-			"location_id": s.LocationID,
-			"time_ids":    s.TimeIDs,
+			"date":          date,
+			"cinema_id":     s.CinemaID,
+			"location_id":   s.LocationID,
+			"auditorium_id": s.AuditoriumID,
+			"time_ids":      s.TimeIDs,
 		})
 	}
 
@@ -368,3 +371,73 @@ func (ac *AdminController) DeleteMovie(c *gin.Context) {
 		Message: "Movie deleted successfully",
 	})
 }
+
+// AssignStaff godoc
+// @Summary Assign staff
+// @Description Give a user the staff role at a cinema so they can check in tickets there
+// @Tags Admin - Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param staff body dtos.AssignStaffRequest true "Cinema the staff member works at"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 404 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/users/{id}/staff [patch]
+// @Security BearerAuth
+func (ac *AdminController) AssignStaff(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid user id",
+		})
+		return
+	}
+
+	var body dtos.AssignStaffRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	if err := ac.adminRepository.AssignStaff(c, userID, body.CinemaID); err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "User not found",
+			})
+			return
+		}
+
+		if errors.Is(err, repositories.ErrCinemaNotFound) {
+			c.JSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: "Cinema not found",
+			})
+			return
+		}
+
+		log.Println("AssignStaff error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to assign staff",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Staff assigned successfully",
+	})
+}
@@ -0,0 +1,391 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Darari17/be-tickitz-full/internal/dtos"
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditoriumController struct {
+	auditoriumRepo *repositories.AuditoriumRepo
+}
+
+func NewAuditoriumController(ar *repositories.AuditoriumRepo) *AuditoriumController {
+	return &AuditoriumController{auditoriumRepo: ar}
+}
+
+func toAuditoriumSeats(seats []dtos.AuditoriumSeatRequest) []models.AuditoriumSeat {
+	result := make([]models.AuditoriumSeat, 0, len(seats))
+	for _, s := range seats {
+		result = append(result, models.AuditoriumSeat{
+			SeatCode:  s.SeatCode,
+			SeatClass: s.SeatClass,
+			Row:       s.Row,
+			Column:    s.Column,
+		})
+	}
+	return result
+}
+
+// GetAuditoriums godoc
+// @Summary List auditoriums
+// @Description List halls, optionally filtered by cinema and location
+// @Tags Admin - Auditoriums
+// @Produce json
+// @Param cinema_id query int false "Cinema ID"
+// @Param location_id query int false "Location ID"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/auditoriums [get]
+// @Security BearerAuth
+func (ac *AuditoriumController) GetAuditoriums(c *gin.Context) {
+	cinemaID, err := strconv.Atoi(c.DefaultQuery("cinema_id", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid cinema id",
+		})
+		return
+	}
+	locationID, err := strconv.Atoi(c.DefaultQuery("location_id", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid location id",
+		})
+		return
+	}
+
+	auditoriums, err := ac.auditoriumRepo.GetAuditoriums(c, cinemaID, locationID)
+	if err != nil {
+		log.Println("GetAuditoriums error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to get auditoriums",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Success",
+		Data:    auditoriums,
+	})
+}
+
+// GetAuditoriumByID godoc
+// @Summary Get auditorium
+// @Description Get a hall with its seat map
+// @Tags Admin - Auditoriums
+// @Produce json
+// @Param id path int true "Auditorium ID"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 404 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/auditoriums/{id} [get]
+// @Security BearerAuth
+func (ac *AuditoriumController) GetAuditoriumByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid auditorium id",
+		})
+		return
+	}
+
+	auditorium, err := ac.auditoriumRepo.GetAuditoriumByID(c, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrAuditoriumNotFound) {
+			c.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Auditorium not found",
+			})
+			return
+		}
+
+		log.Println("GetAuditoriumByID error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to get auditorium",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Success",
+		Data:    auditorium,
+	})
+}
+
+// CreateAuditorium godoc
+// @Summary Create auditorium
+// @Description Add a hall to a cinema at a location. Without seats every cell of the grid becomes a regular seat.
+// @Tags Admin - Auditoriums
+// @Accept json
+// @Produce json
+// @Param auditorium body dtos.CreateAuditoriumRequest true "Hall and optional seat map"
+// @Success 201 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/auditoriums [post]
+// @Security BearerAuth
+func (ac *AuditoriumController) CreateAuditorium(c *gin.Context) {
+	var body dtos.CreateAuditoriumRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	auditorium, err := ac.auditoriumRepo.CreateAuditorium(c, models.Auditorium{
+		CinemaID:   &body.CinemaID,
+		LocationID: &body.LocationID,
+		Name:       body.Name,
+		Rows:       body.Rows,
+		Columns:    body.Columns,
+		Seats:      toAuditoriumSeats(body.Seats),
+	})
+	if err != nil {
+		var layoutErr *repositories.LayoutError
+		if errors.As(err, &layoutErr) {
+			c.JSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: layoutErr.Message,
+			})
+			return
+		}
+
+		log.Println("CreateAuditorium error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to create auditorium",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dtos.Response{
+		Code:    http.StatusCreated,
+		Success: true,
+		Message: "Auditorium created successfully",
+		Data:    auditorium,
+	})
+}
+
+// UpdateAuditorium godoc
+// @Summary Rename auditorium
+// @Tags Admin - Auditoriums
+// @Accept json
+// @Produce json
+// @Param id path int true "Auditorium ID"
+// @Param auditorium body dtos.UpdateAuditoriumRequest true "New name"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 404 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/auditoriums/{id} [patch]
+// @Security BearerAuth
+func (ac *AuditoriumController) UpdateAuditorium(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid auditorium id",
+		})
+		return
+	}
+
+	var body dtos.UpdateAuditoriumRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	if err := ac.auditoriumRepo.UpdateAuditorium(c, id, body.Name); err != nil {
+		if errors.Is(err, repositories.ErrAuditoriumNotFound) {
+			c.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Auditorium not found",
+			})
+			return
+		}
+
+		log.Println("UpdateAuditorium error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to update auditorium",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Auditorium updated successfully",
+	})
+}
+
+// ReplaceLayout godoc
+// @Summary Replace seat map
+// @Description Replace the grid size and seats of a hall. Cells without a seat are gaps. Halls with sold seats cannot be changed.
+// @Tags Admin - Auditoriums
+// @Accept json
+// @Produce json
+// @Param id path int true "Auditorium ID"
+// @Param layout body dtos.AuditoriumLayoutRequest true "Seat map"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 404 {object} dtos.Response
+// @Failure 409 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/auditoriums/{id}/layout [put]
+// @Security BearerAuth
+func (ac *AuditoriumController) ReplaceLayout(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid auditorium id",
+		})
+		return
+	}
+
+	var body dtos.AuditoriumLayoutRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	auditorium, err := ac.auditoriumRepo.ReplaceLayout(c, id, body.Rows, body.Columns, toAuditoriumSeats(body.Seats))
+	if err != nil {
+		var layoutErr *repositories.LayoutError
+		switch {
+		case errors.As(err, &layoutErr):
+			c.JSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: layoutErr.Message,
+			})
+		case errors.Is(err, repositories.ErrAuditoriumNotFound):
+			c.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Auditorium not found",
+			})
+		case errors.Is(err, repositories.ErrAuditoriumInUse):
+			c.JSON(http.StatusConflict, dtos.Response{
+				Code:    http.StatusConflict,
+				Success: false,
+				Message: "Seats of this auditorium were already sold",
+			})
+		default:
+			log.Println("ReplaceLayout error:", err)
+			c.JSON(http.StatusInternalServerError, dtos.Response{
+				Code:    http.StatusInternalServerError,
+				Success: false,
+				Message: "Failed to update seat map",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Seat map updated successfully",
+		Data:    auditorium,
+	})
+}
+
+// DeleteAuditorium godoc
+// @Summary Delete auditorium
+// @Description Soft delete a hall without upcoming schedules
+// @Tags Admin - Auditoriums
+// @Produce json
+// @Param id path int true "Auditorium ID"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 404 {object} dtos.Response
+// @Failure 409 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/auditoriums/{id} [delete]
+// @Security BearerAuth
+func (ac *AuditoriumController) DeleteAuditorium(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid auditorium id",
+		})
+		return
+	}
+
+	if err := ac.auditoriumRepo.DeleteAuditorium(c, id); err != nil {
+		if errors.Is(err, repositories.ErrAuditoriumNotFound) {
+			c.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Auditorium not found",
+			})
+			return
+		}
+
+		if errors.Is(err, repositories.ErrAuditoriumInUse) {
+			c.JSON(http.StatusConflict, dtos.Response{
+				Code:    http.StatusConflict,
+				Success: false,
+				Message: "Auditorium has upcoming schedules",
+			})
+			return
+		}
+
+		log.Println("DeleteAuditorium error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to delete auditorium",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Auditorium deleted successfully",
+	})
+}
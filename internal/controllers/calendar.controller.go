@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/dtos"
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/Darari17/be-tickitz-full/pkg"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CalendarController struct {
+	orderRepo *repositories.OrderRepo
+	userRepo  *repositories.UserRepository
+}
+
+func NewCalendarController(or *repositories.OrderRepo, ur *repositories.UserRepository) *CalendarController {
+	return &CalendarController{orderRepo: or, userRepo: ur}
+}
+
+func calendarFeed(token string) dtos.CalendarFeedResponse {
+	feedURL := utils.GetBaseURL() + "/calendar/" + token + "/bookings.ics"
+	_, rest, _ := strings.Cut(feedURL, "://")
+	return dtos.CalendarFeedResponse{FeedURL: feedURL, WebcalURL: "webcal://" + rest}
+}
+
+// GetFeedURL godoc
+// @Summary Get calendar feed URL
+// @Description Private URL of an iCalendar feed with every booked showtime, for subscribing from a calendar app
+// @Tags Profile
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dtos.Response{data=dtos.CalendarFeedResponse}
+// @Failure 401 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /profile/calendar [get]
+func (cc *CalendarController) GetFeedURL(ctx *gin.Context) {
+	cc.respondFeedURL(ctx, cc.userRepo.GetCalendarToken)
+}
+
+// ResetFeedURL godoc
+// @Summary Reset calendar feed URL
+// @Description Issue a new feed URL; subscriptions to the old one stop updating
+// @Tags Profile
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dtos.Response{data=dtos.CalendarFeedResponse}
+// @Failure 401 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /profile/calendar/reset [post]
+func (cc *CalendarController) ResetFeedURL(ctx *gin.Context) {
+	cc.respondFeedURL(ctx, cc.userRepo.ResetCalendarToken)
+}
+
+func (cc *CalendarController) respondFeedURL(ctx *gin.Context, getToken func(context.Context, uuid.UUID) (string, error)) {
+	user, err := utils.GetUser(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Unauthorized: " + err.Error(),
+		})
+		return
+	}
+
+	token, err := getToken(ctx.Request.Context(), user.ID)
+	if err != nil {
+		log.Println("Calendar token error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to get calendar feed",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Success",
+		Data:    calendarFeed(token),
+	})
+}
+
+// GetFeed godoc
+// @Summary Calendar feed
+// @Description iCalendar feed of the paid bookings of the user the token belongs to. The token in the path is the only credential.
+// @Tags Orders
+// @Produce text/calendar
+// @Param token path string true "Feed token from /profile/calendar"
+// @Success 200 {file} file
+// @Failure 404 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /calendar/{token}/bookings.ics [get]
+func (cc *CalendarController) GetFeed(ctx *gin.Context) {
+	userID, err := cc.userRepo.GetUserIDByCalendarToken(ctx.Request.Context(), ctx.Param("token"))
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Calendar not found",
+			})
+			return
+		}
+
+		log.Println("GetUserIDByCalendarToken error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to get calendar",
+		})
+		return
+	}
+
+	orders, err := cc.orderRepo.GetOrderHistory(ctx.Request.Context(), userID)
+	if err != nil {
+		log.Println("GetOrderHistory error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to get calendar",
+		})
+		return
+	}
+
+	loc := utils.CinemaLocation()
+	calendar := pkg.Calendar{Name: "Tickitz bookings", Refresh: time.Hour}
+	for i := range orders {
+		if orders[i].Status != models.OrderStatusPaid && orders[i].Status != models.OrderStatusCheckedIn {
+			continue
+		}
+		event, err := utils.BookingEvent(&orders[i], loc)
+		if err != nil {
+			log.Println("BookingEvent error:", err)
+			continue
+		}
+		calendar.Events = append(calendar.Events, event)
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=900")
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", calendar.ICS(time.Now()))
+}
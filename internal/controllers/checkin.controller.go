@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Darari17/be-tickitz-full/internal/dtos"
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/Darari17/be-tickitz-full/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type CheckinController struct {
+	checkinRepo *repositories.CheckinRepo
+}
+
+func NewCheckinController(cr *repositories.CheckinRepo) *CheckinController {
+	return &CheckinController{checkinRepo: cr}
+}
+
+// Checkin godoc
+// @Summary Check in a ticket
+// @Description Verify a scanned ticket and admit its seats. Each seat is admitted once; replays are rejected with a reason.
+// @Tags Check-in
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param checkin body dtos.CheckinRequest true "Scanned ticket and optional seats to admit"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 403 {object} dtos.ErrResponse
+// @Failure 404 {object} dtos.ErrResponse
+// @Failure 409 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /checkin [post]
+func (cc *CheckinController) Checkin(ctx *gin.Context) {
+	var req dtos.CheckinRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	user, cinemaID, ok := cc.staffCinema(ctx)
+	if !ok {
+		return
+	}
+
+	var ticket pkg.TicketClaims
+	if err := ticket.VerifyToken(req.Ticket); err != nil {
+		reason := "invalid_ticket"
+		if errors.Is(err, pkg.ErrTicketExpired) {
+			reason = "ticket_expired"
+		} else if !errors.Is(err, pkg.ErrInvalidTicket) {
+			log.Println("VerifyToken error:", err)
+		}
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Ticket rejected",
+			Data:    gin.H{"reason": reason},
+		})
+		return
+	}
+
+	admission, err := cc.checkinRepo.Admit(ctx.Request.Context(), req.Ticket, &ticket, req.SeatCodes, cinemaID, user.ID)
+	if err != nil {
+		var rejected *repositories.CheckinRejectedError
+		if errors.As(err, &rejected) {
+			ctx.JSON(http.StatusConflict, dtos.Response{
+				Code:    http.StatusConflict,
+				Success: false,
+				Message: rejected.Message,
+				Data: gin.H{
+					"reason":      rejected.Reason,
+					"seat_codes":  rejected.SeatCodes,
+					"admitted_at": rejected.AdmittedAt,
+				},
+			})
+			return
+		}
+
+		if errors.Is(err, repositories.ErrOrderNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Order not found",
+			})
+			return
+		}
+
+		log.Println("Admit error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to check in ticket",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Ticket admitted",
+		Data:    admission,
+	})
+}
+
+// GetAdmissionSummary godoc
+// @Summary Get admission summary
+// @Description Seats and orders sold versus admitted for a schedule
+// @Tags Check-in
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 403 {object} dtos.ErrResponse
+// @Failure 404 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /checkin/schedules/{id} [get]
+func (cc *CheckinController) GetAdmissionSummary(ctx *gin.Context) {
+	scheduleID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "invalid schedule id",
+		})
+		return
+	}
+
+	_, cinemaID, ok := cc.staffCinema(ctx)
+	if !ok {
+		return
+	}
+
+	summary, err := cc.checkinRepo.GetAdmissionSummary(ctx.Request.Context(), scheduleID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrScheduleNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Schedule not found",
+			})
+			return
+		}
+
+		log.Println("GetAdmissionSummary error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to fetch admission summary",
+		})
+		return
+	}
+
+	if cinemaID != nil && *cinemaID != summary.CinemaID {
+		ctx.JSON(http.StatusForbidden, dtos.Response{
+			Code:    http.StatusForbidden,
+			Success: false,
+			Message: "Schedule belongs to another cinema",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Data:    summary,
+	})
+}
+
+// staffCinema resolves the cinema the current staff member may admit tickets for.
+// Admins are not bound to a cinema and get a nil cinema ID.
+func (cc *CheckinController) staffCinema(ctx *gin.Context) (*models.UserContext, *int, bool) {
+	user, err := utils.GetUser(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Unauthorized: " + err.Error(),
+		})
+		return nil, nil, false
+	}
+
+	if user.Role == string(models.RoleAdmin) {
+		return user, nil, true
+	}
+
+	cinemaID, err := cc.checkinRepo.GetStaffCinema(ctx.Request.Context(), user.ID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrStaffNotAssigned) {
+			ctx.JSON(http.StatusForbidden, dtos.Response{
+				Code:    http.StatusForbidden,
+				Success: false,
+				Message: "Staff account is not assigned to a cinema",
+			})
+			return nil, nil, false
+		}
+
+		log.Println("GetStaffCinema error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Internal Server Error",
+		})
+		return nil, nil, false
+	}
+	return user, &cinemaID, true
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Darari17/be-tickitz-full/internal/dtos"
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type MailController struct {
+	mailRepo     *repositories.MailRepo
+	reminderRepo *repositories.ReminderRepo
+}
+
+func NewMailController(mr *repositories.MailRepo, rr *repositories.ReminderRepo) *MailController {
+	return &MailController{mailRepo: mr, reminderRepo: rr}
+}
+
+// GetOrderEmails godoc
+// @Summary List order emails
+// @Description Get the delivery log of the emails sent for an order
+// @Tags Admin - Orders
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/orders/{id}/emails [get]
+// @Security BearerAuth
+func (mc *MailController) GetOrderEmails(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid order id",
+		})
+		return
+	}
+
+	emails, err := mc.mailRepo.GetOrderEmails(c, orderID)
+	if err != nil {
+		log.Println("GetOrderEmails error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to get order emails",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Success",
+		Data:    emails,
+	})
+}
+
+// ResendTicketEmail godoc
+// @Summary Resend e-ticket email
+// @Description Queue the e-ticket email of an order again, to the order's current email address
+// @Tags Admin - Orders
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 202 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 404 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/orders/{id}/emails/resend [post]
+// @Security BearerAuth
+func (mc *MailController) ResendTicketEmail(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid order id",
+		})
+		return
+	}
+
+	if err := mc.mailRepo.ResendOrderEmail(c, orderID, models.EmailTicket); err != nil {
+		if errors.Is(err, repositories.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "No e-ticket email for this order",
+			})
+			return
+		}
+
+		log.Println("ResendOrderEmail error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to resend email",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dtos.Response{
+		Code:    http.StatusAccepted,
+		Success: true,
+		Message: "Email queued",
+	})
+}
+
+// GetOrderReminders godoc
+// @Summary List order reminders
+// @Description Get the showtime reminders scheduled for an order and how their delivery went
+// @Tags Admin - Orders
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/orders/{id}/reminders [get]
+// @Security BearerAuth
+func (mc *MailController) GetOrderReminders(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid order id",
+		})
+		return
+	}
+
+	reminders, err := mc.reminderRepo.GetOrderReminders(c, orderID)
+	if err != nil {
+		log.Println("GetOrderReminders error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to get order reminders",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Success",
+		Data:    reminders,
+	})
+}
@@ -1,35 +1,52 @@
 package controllers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/Darari17/be-tickitz-full/internal/dtos"
+	"github.com/Darari17/be-tickitz-full/internal/middlewares"
 	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/payments"
 	"github.com/Darari17/be-tickitz-full/internal/repositories"
 	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/Darari17/be-tickitz-full/pkg"
 	"github.com/gin-gonic/gin"
 )
 
 type OrderController struct {
-	orderRepo *repositories.OrderRepo
+	orderRepo   *repositories.OrderRepo
+	holdRepo    *repositories.SeatHoldRepo
+	paymentRepo *repositories.PaymentRepo
+	providers   *payments.Registry
 }
 
-func NewOrderController(or *repositories.OrderRepo) *OrderController {
-	return &OrderController{orderRepo: or}
+func NewOrderController(or *repositories.OrderRepo, hr *repositories.SeatHoldRepo, pr *repositories.PaymentRepo, providers *payments.Registry) *OrderController {
+	return &OrderController{orderRepo: or, holdRepo: hr, paymentRepo: pr, providers: providers}
 }
 
 // CreateOrder godoc
 // @Summary Create a new order
-// @Description Create a new order and save to database
+// @Description Create a pending order for held seats and start its payment. The response carries the payment redirect. Couple seats must be booked in pairs, companion seats with the wheelchair space beside them, and no single seat may be left empty between booked ones. Points and a promo code can be redeemed as a discount on the tickets. With VERIFY_REQUIRED_FOR_ORDERS set, users who have not verified their email get 403.
 // @Tags Orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param order body dtos.CreateOrderRequest true "Order Data"
+// @Param Idempotency-Key header string false "Repeat-safe request key"
 // @Success 201 {object} dtos.Response
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 403 {object} dtos.ErrResponse
+// @Failure 404 {object} dtos.ErrResponse
+// @Failure 409 {object} dtos.ErrResponse
+// @Failure 422 {object} dtos.ErrResponse
 // @Failure 500 {object} dtos.ErrResponse
+// @Failure 502 {object} dtos.ErrResponse
 // @Router /orders [post]
 // CreateOrder godoc
 func (oc *OrderController) CreateOrder(ctx *gin.Context) {
@@ -53,7 +70,28 @@ func (oc *OrderController) CreateOrder(ctx *gin.Context) {
 		return
 	}
 
-	seatIDs, err := oc.orderRepo.GetSeatIDsByCodes(ctx.Request.Context(), req.SeatCodes)
+	method, err := oc.orderRepo.GetPaymentMethod(ctx.Request.Context(), req.PaymentID)
+	if err != nil {
+		log.Println("GetPaymentMethod error:", err)
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid payment method",
+		})
+		return
+	}
+
+	provider, ok := oc.providers.Get(method.Provider)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Payment method is not available",
+		})
+		return
+	}
+
+	seatIDs, err := oc.orderRepo.GetSeatIDsByCodes(ctx.Request.Context(), req.ScheduleID, req.SeatCodes)
 	if err != nil {
 		log.Println("GetSeatIDsByCodes error:", err)
 		ctx.JSON(http.StatusInternalServerError, dtos.Response{
@@ -64,6 +102,57 @@ func (oc *OrderController) CreateOrder(ctx *gin.Context) {
 		return
 	}
 
+	notHeld, err := oc.holdRepo.NotHeldBy(ctx.Request.Context(), req.ScheduleID, req.SeatCodes, user.ID)
+	if err != nil {
+		log.Println("NotHeldBy error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to verify seat hold",
+		})
+		return
+	}
+
+	if len(notHeld) > 0 {
+		ctx.JSON(http.StatusConflict, dtos.Response{
+			Code:    http.StatusConflict,
+			Success: false,
+			Message: "Seats are not held by you or the hold has expired",
+			Data:    gin.H{"seat_codes": notHeld},
+		})
+		return
+	}
+
+	if err := oc.orderRepo.ValidateSeatSelection(ctx.Request.Context(), req.ScheduleID, user.ID, req.SeatCodes); err != nil {
+		var selectionErr *repositories.SeatSelectionError
+		if errors.As(err, &selectionErr) {
+			ctx.JSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: selectionErr.Message,
+				Data:    gin.H{"reason": selectionErr.Reason, "seat_codes": selectionErr.SeatCodes},
+			})
+			return
+		}
+
+		if errors.Is(err, repositories.ErrScheduleNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Schedule not found",
+			})
+			return
+		}
+
+		log.Println("ValidateSeatSelection error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to validate seat selection",
+		})
+		return
+	}
+
 	order := &models.Order{
 		UserID:     user.ID,
 		ScheduleID: req.ScheduleID,
@@ -71,27 +160,492 @@ func (oc *OrderController) CreateOrder(ctx *gin.Context) {
 		FullName:   req.FullName,
 		Email:      req.Email,
 		Phone:      req.Phone,
+
+		PointsRedeemed: req.Points,
+		PromoCode:      req.PromoCode,
+	}
+
+	createdOrder, charge, err := oc.orderRepo.CreateOrder(ctx.Request.Context(), order, seatIDs, provider)
+	if err != nil {
+		var chargeErr *repositories.ChargeError
+		if errors.As(err, &chargeErr) {
+			log.Println("CreateCharge error:", chargeErr.Err)
+			ctx.JSON(http.StatusBadGateway, dtos.Response{
+				Code:    http.StatusBadGateway,
+				Success: false,
+				Message: "Failed to create payment",
+			})
+			return
+		}
+
+		var takenErr *repositories.SeatTakenError
+		if errors.As(err, &takenErr) {
+			ctx.JSON(http.StatusConflict, dtos.Response{
+				Code:    http.StatusConflict,
+				Success: false,
+				Message: "Seats already taken",
+				Data:    gin.H{"seat_codes": takenErr.SeatCodes},
+			})
+			return
+		}
+
+		if errors.Is(err, repositories.ErrScheduleNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Schedule not found",
+			})
+			return
+		}
+
+		if errors.Is(err, repositories.ErrInsufficientPoints) {
+			ctx.JSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: "Not enough points",
+			})
+			return
+		}
+
+		var promoErr *repositories.PromoRejectedError
+		if errors.As(err, &promoErr) {
+			ctx.JSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: promoErr.Message,
+				Data:    gin.H{"reason": promoErr.Reason},
+			})
+			return
+		}
+
+		if errors.Is(err, repositories.ErrPromoNotFound) {
+			ctx.JSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: "Promo code not found",
+			})
+			return
+		}
+
+		log.Println("CreateOrder error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to create order",
+		})
+		return
+	}
+
+	middlewares.MarkCommitted(ctx)
+
+	if err := oc.holdRepo.Release(ctx.Request.Context(), req.ScheduleID, req.SeatCodes, user.ID); err != nil {
+		log.Println("Release hold error:", err)
+	}
+
+	ctx.JSON(http.StatusCreated, dtos.Response{
+		Code:    http.StatusCreated,
+		Success: true,
+		Data: map[string]interface{}{
+			"order_id":   createdOrder.ID,
+			"reference":  createdOrder.Reference,
+			"status":     createdOrder.Status,
+			"seats":      createdOrder.Seats,
+			"subtotal":   createdOrder.Subtotal,
+			"fees":       createdOrder.Fees,
+			"discount":   createdOrder.Discount,
+			"total":      createdOrder.Total,
+			"points":     createdOrder.PointsRedeemed,
+			"promo_code": createdOrder.PromoCode,
+			"payment":    charge,
+		},
+	})
+}
+
+// CreateHold godoc
+// @Summary Hold seats
+// @Description Temporarily reserve seats of a schedule for the current user before checkout
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param hold body dtos.SeatHoldRequest true "Seats to hold"
+// @Success 201 {object} dtos.Response
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 409 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /orders/holds [post]
+func (oc *OrderController) CreateHold(ctx *gin.Context) {
+	user, err := utils.GetUser(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Unauthorized: " + err.Error(),
+		})
+		return
+	}
+
+	var req dtos.SeatHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	seatIDs, err := oc.orderRepo.GetSeatIDsByCodes(ctx.Request.Context(), req.ScheduleID, req.SeatCodes)
+	if err != nil {
+		log.Println("GetSeatIDsByCodes error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to map seat codes",
+		})
+		return
+	}
+
+	if len(seatIDs) != len(req.SeatCodes) {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid seat codes",
+		})
+		return
+	}
+
+	sold, err := oc.orderRepo.GetTakenSeatCodes(ctx.Request.Context(), req.ScheduleID, req.SeatCodes)
+	if err != nil {
+		log.Println("GetTakenSeatCodes error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to check seats",
+		})
+		return
+	}
+
+	if len(sold) > 0 {
+		ctx.JSON(http.StatusConflict, dtos.Response{
+			Code:    http.StatusConflict,
+			Success: false,
+			Message: "Seats already taken",
+			Data:    gin.H{"seat_codes": sold},
+		})
+		return
+	}
+
+	hold, held, err := oc.holdRepo.Hold(ctx.Request.Context(), req.ScheduleID, req.SeatCodes, user.ID)
+	if err != nil {
+		log.Println("Hold error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to hold seats",
+		})
+		return
+	}
+
+	if len(held) > 0 {
+		ctx.JSON(http.StatusConflict, dtos.Response{
+			Code:    http.StatusConflict,
+			Success: false,
+			Message: "Seats are held by another user",
+			Data:    gin.H{"seat_codes": held},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dtos.Response{
+		Code:    http.StatusCreated,
+		Success: true,
+		Message: "Seats held successfully",
+		Data:    hold,
+	})
+}
+
+// ExtendHold godoc
+// @Summary Extend seat hold
+// @Description Refresh the expiry of seats currently held by the current user
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param hold body dtos.SeatHoldRequest true "Held seats"
+// @Success 200 {object} dtos.Response
+// @Failure 409 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /orders/holds [patch]
+func (oc *OrderController) ExtendHold(ctx *gin.Context) {
+	user, err := utils.GetUser(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Unauthorized: " + err.Error(),
+		})
+		return
+	}
+
+	var req dtos.SeatHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	hold, missing, err := oc.holdRepo.Extend(ctx.Request.Context(), req.ScheduleID, req.SeatCodes, user.ID)
+	if err != nil {
+		log.Println("Extend hold error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to extend hold",
+		})
+		return
+	}
+
+	if len(missing) > 0 {
+		ctx.JSON(http.StatusConflict, dtos.Response{
+			Code:    http.StatusConflict,
+			Success: false,
+			Message: "Seats are not held by you or the hold has expired",
+			Data:    gin.H{"seat_codes": missing},
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Hold extended successfully",
+		Data:    hold,
+	})
+}
+
+// ReleaseHold godoc
+// @Summary Release seat hold
+// @Description Release seats held by the current user
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param hold body dtos.SeatHoldRequest true "Held seats"
+// @Success 200 {object} dtos.Response
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /orders/holds [delete]
+func (oc *OrderController) ReleaseHold(ctx *gin.Context) {
+	user, err := utils.GetUser(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Unauthorized: " + err.Error(),
+		})
+		return
+	}
+
+	var req dtos.SeatHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	if err := oc.holdRepo.Release(ctx.Request.Context(), req.ScheduleID, req.SeatCodes, user.ID); err != nil {
+		log.Println("Release hold error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to release hold",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Hold released successfully",
+	})
+}
+
+// QuotePrice godoc
+// @Summary Get a price quote
+// @Description Price the selected seats of a schedule, including surcharges and fees
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param quote body dtos.PriceQuoteRequest true "Seats to price"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 404 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /orders/quote [post]
+func (oc *OrderController) QuotePrice(ctx *gin.Context) {
+	var req dtos.PriceQuoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	seatIDs, err := oc.orderRepo.GetSeatIDsByCodes(ctx.Request.Context(), req.ScheduleID, req.SeatCodes)
+	if err != nil {
+		log.Println("GetSeatIDsByCodes error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to map seat codes",
+		})
+		return
+	}
+
+	if len(seatIDs) != len(req.SeatCodes) {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid seat codes",
+		})
+		return
+	}
+
+	quote, err := oc.orderRepo.QuotePrice(ctx.Request.Context(), req.ScheduleID, seatIDs)
+	if err != nil {
+		if errors.Is(err, repositories.ErrScheduleNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Schedule not found",
+			})
+			return
+		}
+
+		log.Println("QuotePrice error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to calculate price",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Data:    quote,
+	})
+}
+
+// ValidatePromo godoc
+// @Summary Validate a promo code
+// @Description Check a promo code against the selected seats without using it and return the discounted price
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param promo body dtos.ValidatePromoRequest true "Promo code and seats"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 404 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /orders/promo/validate [post]
+func (oc *OrderController) ValidatePromo(ctx *gin.Context) {
+	user, err := utils.GetUser(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Unauthorized: " + err.Error(),
+		})
+		return
+	}
+
+	var req dtos.ValidatePromoRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	seatIDs, err := oc.orderRepo.GetSeatIDsByCodes(ctx.Request.Context(), req.ScheduleID, req.SeatCodes)
+	if err != nil {
+		log.Println("GetSeatIDsByCodes error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to map seat codes",
+		})
+		return
+	}
+
+	if len(seatIDs) != len(req.SeatCodes) {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid seat codes",
+		})
+		return
 	}
 
-	createdOrder, err := oc.orderRepo.CreateOrder(ctx.Request.Context(), order, seatIDs)
+	quote, err := oc.orderRepo.QuotePromo(ctx.Request.Context(), req.Code, user.ID, req.ScheduleID, req.PaymentID, seatIDs)
 	if err != nil {
-		log.Println("CreateOrder error:", err)
+		var promoErr *repositories.PromoRejectedError
+		if errors.As(err, &promoErr) {
+			ctx.JSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: promoErr.Message,
+				Data:    gin.H{"reason": promoErr.Reason},
+			})
+			return
+		}
+
+		if errors.Is(err, repositories.ErrPromoNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Promo code not found",
+			})
+			return
+		}
+
+		if errors.Is(err, repositories.ErrScheduleNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Schedule not found",
+			})
+			return
+		}
+
+		log.Println("QuotePromo error:", err)
 		ctx.JSON(http.StatusInternalServerError, dtos.Response{
 			Code:    http.StatusInternalServerError,
 			Success: false,
-			Message: "Failed to create order",
+			Message: "Failed to validate promo code",
 		})
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, dtos.Response{
-		Code:    http.StatusCreated,
+	ctx.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
 		Success: true,
-		Data: map[string]interface{}{
-			"order_id": createdOrder.ID,
-			"qr_code":  createdOrder.QRCode,
-			"seats":    createdOrder.Seats,
-		},
+		Message: "Promo code is valid",
+		Data:    quote,
 	})
 }
 
@@ -150,12 +704,13 @@ func (oc *OrderController) GetSchedules(ctx *gin.Context) {
 
 // GetAvailableSeats godoc
 // @Summary Get available seats
-// @Description Retrieve available seats for a schedule
+// @Description Retrieve the seat map of a schedule's auditorium with the status of every seat
 // @Tags Orders
 // @Produce json
 // @Security BearerAuth
 // @Param schedule_id query int true "Schedule ID"
 // @Success 200 {object} dtos.Response
+// @Failure 404 {object} dtos.ErrResponse
 // @Failure 500 {object} dtos.ErrResponse
 // @Router /orders/seats [get]
 func (oc *OrderController) GetAvailableSeats(ctx *gin.Context) {
@@ -179,8 +734,17 @@ func (oc *OrderController) GetAvailableSeats(ctx *gin.Context) {
 		return
 	}
 
-	seats, err := oc.orderRepo.GetAvailableSeats(ctx.Request.Context(), scheduleID)
+	layout, err := oc.orderRepo.GetAvailableSeats(ctx.Request.Context(), scheduleID)
 	if err != nil {
+		if errors.Is(err, repositories.ErrScheduleNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Schedule not found",
+			})
+			return
+		}
+
 		log.Println("GetAvailableSeats error:", err)
 		ctx.JSON(http.StatusInternalServerError, dtos.Response{
 			Code:    http.StatusInternalServerError,
@@ -193,48 +757,188 @@ func (oc *OrderController) GetAvailableSeats(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, dtos.Response{
 		Code:    http.StatusOK,
 		Success: true,
-		Data:    seats,
+		Data:    layout,
 	})
 }
 
 // GetTransactionDetail godoc
 // @Summary Get transaction detail
-// @Description Retrieve detail of a transaction
+// @Description Retrieve detail of one of the current user's orders
 // @Tags Orders
 // @Produce json
 // @Security BearerAuth
-// @Param id path int true "Order ID"
+// @Param reference path string true "Order reference" example(7KQ2M9XD4R)
 // @Success 200 {object} dtos.Response
+// @Failure 404 {object} dtos.ErrResponse
 // @Failure 500 {object} dtos.ErrResponse
-// @Router /orders/{id} [get]
+// @Router /orders/{reference} [get]
 func (oc *OrderController) GetTransactionDetail(ctx *gin.Context) {
-	idStr := ctx.Param("id")
-	orderID, err := strconv.Atoi(idStr)
-	if err != nil {
+	detail, ok := oc.getOwnedOrder(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Data:    detail,
+	})
+}
+
+// GetTicketQRCode godoc
+// @Summary Get ticket QR code
+// @Description Render the signed ticket token of a paid order as a QR code image
+// @Tags Orders
+// @Produce png
+// @Produce image/svg+xml
+// @Security BearerAuth
+// @Param reference path string true "Order reference" example(7KQ2M9XD4R)
+// @Param format query string false "Image format (png or svg)" default(png)
+// @Param scale query int false "Pixels per module (1-32)" default(8)
+// @Success 200 {file} file
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 404 {object} dtos.ErrResponse
+// @Failure 409 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /orders/{reference}/qrcode [get]
+func (oc *OrderController) GetTicketQRCode(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", "png")
+	scale, err := strconv.Atoi(ctx.DefaultQuery("scale", "8"))
+	if (format != "png" && format != "svg") || err != nil || scale < 1 || scale > 32 {
 		ctx.JSON(http.StatusBadRequest, dtos.Response{
 			Code:    http.StatusBadRequest,
 			Success: false,
-			Message: "invalid order id",
+			Message: "format must be png or svg and scale between 1 and 32",
+		})
+		return
+	}
+
+	detail, ok := oc.getOwnedOrder(ctx)
+	if !ok {
+		return
+	}
+
+	if detail.Status != models.OrderStatusPaid && detail.Status != models.OrderStatusCheckedIn {
+		ctx.JSON(http.StatusConflict, dtos.Response{
+			Code:    http.StatusConflict,
+			Success: false,
+			Message: fmt.Sprintf("No ticket for an order in status %s", detail.Status),
 		})
 		return
 	}
 
-	detail, err := oc.orderRepo.GetTransactionDetail(ctx.Request.Context(), orderID)
+	qr, err := pkg.NewQRCode(detail.QRCode)
 	if err != nil {
-		log.Println("GetTransactionDetail error:", err)
+		log.Println("NewQRCode error:", err)
 		ctx.JSON(http.StatusInternalServerError, dtos.Response{
 			Code:    http.StatusInternalServerError,
 			Success: false,
-			Message: "Failed to fetch order detail",
+			Message: "Failed to render ticket",
 		})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, dtos.Response{
-		Code:    http.StatusOK,
-		Success: true,
-		Data:    detail,
-	})
+	if format == "svg" {
+		ctx.Data(http.StatusOK, "image/svg+xml", []byte(qr.SVG(scale)))
+		return
+	}
+
+	img, err := qr.PNG(scale)
+	if err != nil {
+		log.Println("QRCode PNG error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to render ticket",
+		})
+		return
+	}
+	ctx.Data(http.StatusOK, "image/png", img)
+}
+
+// GetTicketPDF godoc
+// @Summary Download ticket PDF
+// @Description Download a printable ticket of a paid order with the poster, showtime, seats and QR code
+// @Tags Orders
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param reference path string true "Order reference" example(7KQ2M9XD4R)
+// @Success 200 {file} file
+// @Failure 404 {object} dtos.ErrResponse
+// @Failure 409 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /orders/{reference}/ticket.pdf [get]
+func (oc *OrderController) GetTicketPDF(ctx *gin.Context) {
+	detail, ok := oc.getOwnedOrder(ctx)
+	if !ok {
+		return
+	}
+
+	if detail.Status != models.OrderStatusPaid && detail.Status != models.OrderStatusCheckedIn {
+		ctx.JSON(http.StatusConflict, dtos.Response{
+			Code:    http.StatusConflict,
+			Success: false,
+			Message: fmt.Sprintf("No ticket for an order in status %s", detail.Status),
+		})
+		return
+	}
+
+	pdf, err := utils.TicketPDF(detail, "public")
+	if err != nil {
+		log.Println("TicketPDF error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to render ticket",
+		})
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tickitz-%s.pdf"`, detail.Reference))
+	ctx.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// GetTicketCalendar godoc
+// @Summary Add booking to calendar
+// @Description Download an iCalendar event for the showtime of a paid order
+// @Tags Orders
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param reference path string true "Order reference" example(7KQ2M9XD4R)
+// @Success 200 {file} file
+// @Failure 404 {object} dtos.ErrResponse
+// @Failure 409 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /orders/{reference}/calendar.ics [get]
+func (oc *OrderController) GetTicketCalendar(ctx *gin.Context) {
+	detail, ok := oc.getOwnedOrder(ctx)
+	if !ok {
+		return
+	}
+
+	if detail.Status != models.OrderStatusPaid && detail.Status != models.OrderStatusCheckedIn {
+		ctx.JSON(http.StatusConflict, dtos.Response{
+			Code:    http.StatusConflict,
+			Success: false,
+			Message: fmt.Sprintf("No ticket for an order in status %s", detail.Status),
+		})
+		return
+	}
+
+	event, err := utils.BookingEvent(detail, utils.CinemaLocation())
+	if err != nil {
+		log.Println("BookingEvent error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to create calendar event",
+		})
+		return
+	}
+
+	calendar := pkg.Calendar{Events: []pkg.CalendarEvent{event}}
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tickitz-%s.ics"`, detail.Reference))
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", calendar.ICS(time.Now()))
 }
 
 // GetOrderHistory godoc
@@ -275,6 +979,307 @@ func (oc *OrderController) GetOrderHistory(ctx *gin.Context) {
 	})
 }
 
+// UpdateOrderStatus godoc
+// @Summary Update order status
+// @Description Move an order to another status following the order lifecycle
+// @Tags Admin - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param body body dtos.UpdateOrderStatusRequest true "New status"
+// @Success 200 {object} dtos.Response
+// @Failure 404 {object} dtos.ErrResponse
+// @Failure 422 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /admin/orders/{id}/status [patch]
+func (oc *OrderController) UpdateOrderStatus(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "invalid order id",
+		})
+		return
+	}
+
+	var req dtos.UpdateOrderStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	err = oc.orderRepo.UpdateStatus(ctx.Request.Context(), orderID, models.OrderStatus(req.Status), req.Note)
+	if err != nil {
+		if errors.Is(err, repositories.ErrOrderNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Order not found",
+			})
+			return
+		}
+
+		var transitionErr *repositories.InvalidTransitionError
+		if errors.As(err, &transitionErr) {
+			ctx.JSON(http.StatusUnprocessableEntity, dtos.Response{
+				Code:    http.StatusUnprocessableEntity,
+				Success: false,
+				Message: transitionErr.Error(),
+			})
+			return
+		}
+
+		log.Println("UpdateStatus error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to update order status",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Order status updated successfully",
+	})
+}
+
+// getOwnedOrder loads the order named by the reference path parameter. Orders of other
+// users are reported as not found so references cannot be probed; admins see every order.
+func (oc *OrderController) getOwnedOrder(ctx *gin.Context) (*models.OrderDetail, bool) {
+	user, err := utils.GetUser(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Unauthorized: " + err.Error(),
+		})
+		return nil, false
+	}
+
+	detail, err := oc.orderRepo.GetTransactionDetail(ctx.Request.Context(), ctx.Param("reference"))
+	if err == nil && detail.UserID != user.ID && user.Role != string(models.RoleAdmin) {
+		err = repositories.ErrOrderNotFound
+	}
+	if err != nil {
+		if errors.Is(err, repositories.ErrOrderNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Order not found",
+			})
+			return nil, false
+		}
+
+		log.Println("GetTransactionDetail error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to fetch order detail",
+		})
+		return nil, false
+	}
+	return detail, true
+}
+
+// CancelOrder godoc
+// @Summary Cancel an order
+// @Description Cancel the whole order or some of its seats before the cancellation cutoff. Paid seats are refunded through the payment provider.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param reference path string true "Order reference" example(7KQ2M9XD4R)
+// @Param body body dtos.CancelOrderRequest false "Seats to cancel, all when empty"
+// @Param Idempotency-Key header string false "Repeat-safe request key"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 404 {object} dtos.ErrResponse
+// @Failure 409 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /orders/{reference}/cancel [post]
+func (oc *OrderController) CancelOrder(ctx *gin.Context) {
+	var req dtos.CancelOrderRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: "Invalid request: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	user, err := utils.GetUser(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Unauthorized: " + err.Error(),
+		})
+		return
+	}
+
+	orderID, err := oc.orderRepo.GetOrderIDByReference(ctx.Request.Context(), ctx.Param("reference"))
+	if err != nil {
+		if errors.Is(err, repositories.ErrOrderNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Order not found",
+			})
+			return
+		}
+
+		log.Println("GetOrderIDByReference error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to cancel order",
+		})
+		return
+	}
+
+	oc.cancelOrder(ctx, orderID, repositories.CancelOptions{
+		UserID:      &user.ID,
+		RequestedBy: user.ID,
+		SeatCodes:   req.SeatCodes,
+		Reason:      req.Reason,
+	})
+}
+
+// AdminCancelOrder godoc
+// @Summary Cancel an order (admin)
+// @Description Cancel any order or some of its seats regardless of the cancellation cutoff
+// @Tags Admin - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param body body dtos.AdminCancelOrderRequest true "Reason and seats to cancel, all when empty"
+// @Param Idempotency-Key header string false "Repeat-safe request key"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 404 {object} dtos.ErrResponse
+// @Failure 409 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /admin/orders/{id}/cancel [post]
+func (oc *OrderController) AdminCancelOrder(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "invalid order id",
+		})
+		return
+	}
+
+	var req dtos.AdminCancelOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	user, err := utils.GetUser(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Unauthorized: " + err.Error(),
+		})
+		return
+	}
+
+	oc.cancelOrder(ctx, orderID, repositories.CancelOptions{
+		RequestedBy: user.ID,
+		SeatCodes:   req.SeatCodes,
+		Reason:      req.Reason,
+		Override:    true,
+	})
+}
+
+func (oc *OrderController) cancelOrder(ctx *gin.Context, orderID int, opts repositories.CancelOptions) {
+	cancellation, err := oc.orderRepo.CancelOrder(ctx.Request.Context(), orderID, opts)
+	if err != nil {
+		if errors.Is(err, repositories.ErrOrderNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Order not found",
+			})
+			return
+		}
+
+		var rejected *repositories.CancelRejectedError
+		if errors.As(err, &rejected) {
+			ctx.JSON(http.StatusConflict, dtos.Response{
+				Code:    http.StatusConflict,
+				Success: false,
+				Message: rejected.Message,
+				Data: gin.H{
+					"reason":     rejected.Reason,
+					"seat_codes": rejected.SeatCodes,
+				},
+			})
+			return
+		}
+
+		log.Println("CancelOrder error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to cancel order",
+		})
+		return
+	}
+
+	middlewares.MarkCommitted(ctx)
+
+	if refund := cancellation.Refund; refund != nil && refund.Status == models.RefundPending {
+		settleRefund(ctx.Request.Context(), oc.providers, oc.paymentRepo, refund)
+	}
+
+	ctx.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Order cancelled",
+		Data:    cancellation,
+	})
+}
+
+// settleRefund asks the payment provider to return the money. Failures are kept on the
+// refund record so they can be followed up; whatever queued the refund stands.
+func settleRefund(ctx context.Context, providers *payments.Registry, paymentRepo *repositories.PaymentRepo, refund *models.OrderRefund) {
+	refund.Status = models.RefundFailed
+
+	provider, ok := providers.Get(*refund.Provider)
+	if !ok {
+		log.Println("Refund error: unknown payment provider", *refund.Provider)
+	} else if result, err := provider.Refund(ctx, *refund.ChargeReference, refund.Amount); err != nil {
+		log.Println("Refund error:", err)
+	} else {
+		refund.Reference = &result.Reference
+		refund.Status = models.RefundRefunded
+	}
+
+	if err := paymentRepo.CompleteRefund(ctx, refund); err != nil {
+		log.Println("CompleteRefund error:", err)
+	}
+}
+
 // GetPayments godoc
 // @Summary Get all payment methods
 // @Description Retrieve list of available payment methods
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/Darari17/be-tickitz-full/internal/dtos"
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/payments"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentController struct {
+	paymentRepo *repositories.PaymentRepo
+	providers   *payments.Registry
+	fake        *payments.FakeProvider
+}
+
+func NewPaymentController(pr *repositories.PaymentRepo, providers *payments.Registry, fake *payments.FakeProvider) *PaymentController {
+	return &PaymentController{paymentRepo: pr, providers: providers, fake: fake}
+}
+
+// Webhook godoc
+// @Summary Payment webhook
+// @Description Receive a signed charge notification from a payment provider and mark the order paid. A payment the order cannot accept is refunded.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name" example(fake)
+// @Success 200 {object} dtos.Response
+// @Failure 401 {object} dtos.ErrResponse
+// @Failure 404 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /payments/webhook/{provider} [post]
+func (pc *PaymentController) Webhook(ctx *gin.Context) {
+	provider, ok := pc.providers.Get(ctx.Param("provider"))
+	if !ok {
+		ctx.JSON(http.StatusNotFound, dtos.Response{
+			Code:    http.StatusNotFound,
+			Success: false,
+			Message: "Unknown payment provider",
+		})
+		return
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	event, err := provider.VerifyWebhook(ctx.Request.Header, body)
+	if err != nil {
+		log.Println("VerifyWebhook error:", err)
+		ctx.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Invalid webhook signature",
+		})
+		return
+	}
+
+	pc.applyEvent(ctx, provider.Name(), event)
+}
+
+// FakePay godoc
+// @Summary Complete a fake payment
+// @Description Development checkout page of the in-process fake provider. Settles the charge and delivers its webhook.
+// @Tags Payments
+// @Produce json
+// @Param reference path string true "Charge reference"
+// @Param status query string false "Resulting charge status (paid or failed)" default(paid)
+// @Success 200 {object} dtos.Response
+// @Failure 404 {object} dtos.ErrResponse
+// @Router /payments/fake/{reference}/pay [get]
+func (pc *PaymentController) FakePay(ctx *gin.Context) {
+	status := payments.ChargeStatus(ctx.DefaultQuery("status", string(payments.ChargePaid)))
+	if status != payments.ChargePaid && status != payments.ChargeFailed {
+		ctx.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "status must be paid or failed",
+		})
+		return
+	}
+
+	header, body, err := pc.fake.Complete(ctx.Param("reference"), status)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dtos.Response{
+			Code:    http.StatusNotFound,
+			Success: false,
+			Message: "Charge not found",
+		})
+		return
+	}
+
+	event, err := pc.fake.VerifyWebhook(header, body)
+	if err != nil {
+		log.Println("VerifyWebhook error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Internal Server Error",
+		})
+		return
+	}
+
+	pc.applyEvent(ctx, pc.fake.Name(), event)
+}
+
+func (pc *PaymentController) applyEvent(ctx *gin.Context, provider string, event *payments.WebhookEvent) {
+	orderID, refund, err := pc.paymentRepo.ApplyWebhookEvent(ctx.Request.Context(), provider, event)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPaymentNotFound) {
+			ctx.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Payment not found",
+			})
+			return
+		}
+
+		log.Println("ApplyWebhookEvent error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to process payment",
+		})
+		return
+	}
+
+	if refund != nil && refund.Status == models.RefundPending {
+		settleRefund(ctx.Request.Context(), pc.providers, pc.paymentRepo, refund)
+	}
+
+	ctx.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Payment processed",
+		Data: gin.H{
+			"order_id":  orderID,
+			"reference": event.Reference,
+			"status":    event.Status,
+			"refund":    refund,
+		},
+	})
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Darari17/be-tickitz-full/internal/dtos"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PointController struct {
+	pointRepo *repositories.PointRepo
+}
+
+func NewPointController(pr *repositories.PointRepo) *PointController {
+	return &PointController{pointRepo: pr}
+}
+
+// GetPointHistory godoc
+// @Summary Get points history
+// @Description Points balance of the logged in user and every change to it, newest first
+// @Tags Profile
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dtos.Response
+// @Failure 401 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /profile/points [get]
+func (pc *PointController) GetPointHistory(ctx *gin.Context) {
+	user, err := utils.GetUser(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Unauthorized: " + err.Error(),
+		})
+		return
+	}
+
+	history, err := pc.pointRepo.GetPointHistory(ctx.Request.Context(), user.ID)
+	if err != nil {
+		log.Println("GetPointHistory error:", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to get points history",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Get points history successfully",
+		Data:    history,
+	})
+}
@@ -0,0 +1,308 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Darari17/be-tickitz-full/internal/dtos"
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type PromoController struct {
+	promoRepo *repositories.PromoRepo
+}
+
+func NewPromoController(pr *repositories.PromoRepo) *PromoController {
+	return &PromoController{promoRepo: pr}
+}
+
+// toPromo checks the rules that binding tags cannot express and fills a promo code.
+func toPromo(rules dtos.PromoRules, promo *models.PromoCode) string {
+	if rules.DiscountType == string(models.PromoPercent) && rules.DiscountValue > 100 {
+		return "Percentage discount cannot exceed 100"
+	}
+	if rules.StartsAt != nil && rules.EndsAt != nil && !rules.EndsAt.After(*rules.StartsAt) {
+		return "ends_at must be after starts_at"
+	}
+
+	promo.Description = rules.Description
+	promo.DiscountType = models.PromoType(rules.DiscountType)
+	promo.DiscountValue = rules.DiscountValue
+	promo.MaxDiscount = rules.MaxDiscount
+	promo.MinTickets = max(rules.MinTickets, 1)
+	promo.StartsAt = rules.StartsAt
+	promo.EndsAt = rules.EndsAt
+	promo.MovieIDs = rules.MovieIDs
+	promo.CinemaIDs = rules.CinemaIDs
+	promo.PaymentIDs = rules.PaymentIDs
+	promo.UsageLimit = rules.UsageLimit
+	promo.PerUserLimit = rules.PerUserLimit
+	return ""
+}
+
+// GetPromos godoc
+// @Summary List promo codes
+// @Tags Admin - Promos
+// @Produce json
+// @Success 200 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/promos [get]
+// @Security BearerAuth
+func (pc *PromoController) GetPromos(c *gin.Context) {
+	promos, err := pc.promoRepo.GetPromos(c)
+	if err != nil {
+		log.Println("GetPromos error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to get promo codes",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Success",
+		Data:    promos,
+	})
+}
+
+// GetPromoByID godoc
+// @Summary Get promo code
+// @Description Get a promo code with its restrictions and usage count
+// @Tags Admin - Promos
+// @Produce json
+// @Param id path int true "Promo ID"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 404 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/promos/{id} [get]
+// @Security BearerAuth
+func (pc *PromoController) GetPromoByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid promo id",
+		})
+		return
+	}
+
+	promo, err := pc.promoRepo.GetPromoByID(c, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPromoNotFound) {
+			c.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Promo code not found",
+			})
+			return
+		}
+
+		log.Println("GetPromoByID error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to get promo code",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Success",
+		Data:    promo,
+	})
+}
+
+// CreatePromo godoc
+// @Summary Create promo code
+// @Description Percentage or fixed discount on the tickets of an order, optionally limited to movies, cinemas, payment methods, dates and number of uses
+// @Tags Admin - Promos
+// @Accept json
+// @Produce json
+// @Param promo body dtos.CreatePromoRequest true "Promo code"
+// @Success 201 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 409 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/promos [post]
+// @Security BearerAuth
+func (pc *PromoController) CreatePromo(c *gin.Context) {
+	var body dtos.CreatePromoRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	promo := models.PromoCode{Code: body.Code}
+	if problem := toPromo(body.PromoRules, &promo); problem != "" {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: problem,
+		})
+		return
+	}
+
+	created, err := pc.promoRepo.CreatePromo(c, &promo)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPromoExists) {
+			c.JSON(http.StatusConflict, dtos.Response{
+				Code:    http.StatusConflict,
+				Success: false,
+				Message: "Promo code already exists",
+			})
+			return
+		}
+
+		log.Println("CreatePromo error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to create promo code",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dtos.Response{
+		Code:    http.StatusCreated,
+		Success: true,
+		Message: "Promo code created successfully",
+		Data:    created,
+	})
+}
+
+// UpdatePromo godoc
+// @Summary Update promo code
+// @Description Replace the discount and restrictions of a promo code. The code and its usage count are kept.
+// @Tags Admin - Promos
+// @Accept json
+// @Produce json
+// @Param id path int true "Promo ID"
+// @Param promo body dtos.PromoRules true "Promo rules"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 404 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/promos/{id} [put]
+// @Security BearerAuth
+func (pc *PromoController) UpdatePromo(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid promo id",
+		})
+		return
+	}
+
+	var body dtos.PromoRules
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	promo := models.PromoCode{ID: id}
+	if problem := toPromo(body, &promo); problem != "" {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: problem,
+		})
+		return
+	}
+
+	updated, err := pc.promoRepo.UpdatePromo(c, &promo)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPromoNotFound) {
+			c.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Promo code not found",
+			})
+			return
+		}
+
+		log.Println("UpdatePromo error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to update promo code",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Promo code updated successfully",
+		Data:    updated,
+	})
+}
+
+// DeletePromo godoc
+// @Summary Delete promo code
+// @Description Soft delete a promo code. Orders that already used it keep their discount.
+// @Tags Admin - Promos
+// @Produce json
+// @Param id path int true "Promo ID"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.Response
+// @Failure 404 {object} dtos.Response
+// @Failure 500 {object} dtos.Response
+// @Router /admin/promos/{id} [delete]
+// @Security BearerAuth
+func (pc *PromoController) DeletePromo(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid promo id",
+		})
+		return
+	}
+
+	if err := pc.promoRepo.DeletePromo(c, id); err != nil {
+		if errors.Is(err, repositories.ErrPromoNotFound) {
+			c.JSON(http.StatusNotFound, dtos.Response{
+				Code:    http.StatusNotFound,
+				Success: false,
+				Message: "Promo code not found",
+			})
+			return
+		}
+
+		log.Println("DeletePromo error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to delete promo code",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Promo code deleted successfully",
+	})
+}
@@ -1,10 +1,17 @@
 package controllers
 
 import (
+	"context"
+	"errors"
 	"log"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/Darari17/be-tickitz-full/internal/dtos"
+	"github.com/Darari17/be-tickitz-full/internal/mailer"
 	"github.com/Darari17/be-tickitz-full/internal/models"
 	"github.com/Darari17/be-tickitz-full/internal/repositories"
 	"github.com/Darari17/be-tickitz-full/internal/utils"
@@ -14,22 +21,54 @@ import (
 
 type UserController struct {
 	userRepository *repositories.UserRepository
+	refreshTokens  *repositories.RefreshTokenRepo
+	denylist       *repositories.TokenDenylist
+	userTokens     *repositories.UserTokenRepo
+	loginGuard     *repositories.LoginGuard
+	transport      mailer.Transport
+
+	verifyTTL            time.Duration
+	verifyResendInterval time.Duration
+	verifyResendPerHour  int
+	// verifyRequired turns away logins of users who have not verified their email.
+	verifyRequired bool
+
+	resetTTL      time.Duration
+	resetURL      string
+	resetInterval time.Duration
+	resetPerHour  int
 }
 
-func NewUserController(ur *repositories.UserRepository) *UserController {
+func NewUserController(ur *repositories.UserRepository, rt *repositories.RefreshTokenRepo, dl *repositories.TokenDenylist, ut *repositories.UserTokenRepo, lg *repositories.LoginGuard, transport mailer.Transport) *UserController {
 	return &UserController{
-		userRepository: ur,
+		userRepository:       ur,
+		refreshTokens:        rt,
+		denylist:             dl,
+		userTokens:           ut,
+		loginGuard:           lg,
+		transport:            transport,
+		verifyTTL:            utils.GetEnvDuration("VERIFY_TOKEN_TTL", 24*time.Hour),
+		verifyResendInterval: utils.GetEnvDuration("VERIFY_RESEND_INTERVAL", time.Minute),
+		verifyResendPerHour:  utils.GetEnvInt("VERIFY_RESEND_PER_HOUR", 5),
+		verifyRequired:       utils.GetEnvBool("VERIFY_REQUIRED_FOR_LOGIN", false),
+		resetTTL:             utils.GetEnvDuration("RESET_TOKEN_TTL", time.Hour),
+		resetURL:             utils.GetEnv("RESET_PASSWORD_URL", "http://localhost:5173/reset-password"),
+		resetInterval:        utils.GetEnvDuration("RESET_REQUEST_INTERVAL", time.Minute),
+		resetPerHour:         utils.GetEnvInt("RESET_REQUESTS_PER_HOUR", 5),
 	}
 }
 
 // Login godoc
 // @Summary User login
-// @Description Authenticate user with email and password
+// @Description Authenticate user with email and password. With VERIFY_REQUIRED_FOR_LOGIN set, users who have not verified their email get 403. Repeated failures slow down further attempts on the account and eventually lock it, and too many from one address block it; both answer 429 with Retry-After.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param body body dtos.UserRequest true "User login credentials"
 // @Success 200 {object} dtos.Response
+// @Failure 401 {object} dtos.ErrResponse
+// @Failure 403 {object} dtos.ErrResponse
+// @Failure 429 {object} dtos.ErrResponse
 // @Failure 500 {object} dtos.ErrResponse
 // @Router /auth/login [post]
 func (uc *UserController) Login(c *gin.Context) {
@@ -45,9 +84,11 @@ func (uc *UserController) Login(c *gin.Context) {
 		return
 	}
 
-	user, err := uc.userRepository.GetEmail(c.Request.Context(), body.Email)
+	// The attempt is reserved before the argon2 comparison, so a flood of guesses, even
+	// concurrent ones, costs no hashing.
+	attempt, wait, locked, err := uc.loginGuard.Begin(c.Request.Context(), body.Email, c.ClientIP())
 	if err != nil {
-		log.Println(err.Error())
+		log.Println("Login guard error:", err)
 		c.JSON(http.StatusInternalServerError, dtos.Response{
 			Code:    http.StatusInternalServerError,
 			Success: false,
@@ -56,12 +97,36 @@ func (uc *UserController) Login(c *gin.Context) {
 		})
 		return
 	}
+	if wait > 0 {
+		message := "Too many failed login attempts, please try again later"
+		if locked {
+			message = "This account is locked after too many failed login attempts. Follow the link sent to its email to unlock it, or try again later"
+		}
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, dtos.Response{
+			Code:    http.StatusTooManyRequests,
+			Success: false,
+			Message: message,
+			Data:    nil,
+		})
+		return
+	}
 
-	if user == nil {
-		c.JSON(http.StatusUnauthorized, dtos.Response{
-			Code:    http.StatusUnauthorized,
+	user, err := uc.userRepository.GetEmail(c.Request.Context(), body.Email)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			uc.loginFailed(c, body.Email, attempt, nil)
+			return
+		}
+
+		if err := uc.loginGuard.Release(c.Request.Context(), body.Email, c.ClientIP(), attempt); err != nil {
+			log.Println("Login guard error:", err)
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
 			Success: false,
-			Message: "Invalid Email or Password",
+			Message: "Something went wrong",
 			Data:    nil,
 		})
 		return
@@ -69,17 +134,136 @@ func (uc *UserController) Login(c *gin.Context) {
 
 	hash := pkg.HashConfig{}
 	valid, err := hash.CompareHashAndPassword(body.Password, user.Password)
+	if err != nil {
+		log.Println(err.Error())
+	}
 	if err != nil || !valid {
+		uc.loginFailed(c, body.Email, attempt, user)
+		return
+	}
+
+	if err := uc.loginGuard.Succeed(c.Request.Context(), body.Email, c.ClientIP(), attempt); err != nil {
+		log.Println("Login guard error:", err)
+	}
+
+	if uc.verifyRequired && user.VerifiedAt == nil {
+		c.JSON(http.StatusForbidden, dtos.Response{
+			Code:    http.StatusForbidden,
+			Success: false,
+			Message: "Please verify your email before logging in",
+			Data:    nil,
+		})
+		return
+	}
+
+	refreshToken, err := uc.refreshTokens.Issue(c.Request.Context(), user.ID)
+	if err != nil {
 		log.Println(err.Error())
-		c.JSON(http.StatusUnauthorized, dtos.Response{
-			Code:    http.StatusUnauthorized,
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
 			Success: false,
-			Message: "Invalid Email or Password",
+			Message: "Failed to Generate Token",
 			Data:    nil,
 		})
 		return
 	}
 
+	uc.respondTokens(c, user, refreshToken, "Login Successfully")
+}
+
+// loginFailed counts a failed login and answers it. When it locks an existing account,
+// the owner is mailed a link to unlock it.
+func (uc *UserController) loginFailed(c *gin.Context, email, attempt string, user *models.User) {
+	locked, err := uc.loginGuard.Fail(c.Request.Context(), email, c.ClientIP(), attempt)
+	if err != nil {
+		log.Println("Login guard error:", err)
+	}
+	if locked && user != nil {
+		go uc.sendUnlock(context.WithoutCancel(c.Request.Context()), user)
+	}
+
+	c.JSON(http.StatusUnauthorized, dtos.Response{
+		Code:    http.StatusUnauthorized,
+		Success: false,
+		Message: "Invalid Email or Password",
+		Data:    nil,
+	})
+}
+
+func (uc *UserController) sendUnlock(ctx context.Context, user *models.User) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	lockedFor := uc.loginGuard.LockDuration()
+	token, err := uc.userTokens.Issue(ctx, user.ID, models.UserTokenUnlockAccount, lockedFor)
+	if err != nil {
+		log.Println("Unlock token error:", err)
+		return
+	}
+
+	link := utils.GetBaseURL() + "/auth/unlock?token=" + url.QueryEscape(token)
+	msg, err := mailer.UnlockEmail(user.Email, link, lockedFor)
+	if err == nil {
+		err = uc.transport.Send(ctx, msg)
+	}
+	if err != nil {
+		log.Println("Unlock email error:", err)
+	}
+}
+
+// UnlockAccount godoc
+// @Summary Unlock account
+// @Description Lift a login lockout with the token from the email sent when the account was locked. Each token works once.
+// @Tags Auth
+// @Produce json
+// @Param token query string true "Unlock token"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /auth/unlock [get]
+func (uc *UserController) UnlockAccount(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Token is required",
+		})
+		return
+	}
+
+	email, err := uc.userTokens.UnlockAccount(c.Request.Context(), token)
+	if err == nil {
+		err = uc.loginGuard.Unlock(c.Request.Context(), email)
+	}
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserTokenInvalid) {
+			c.JSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: "Unlock link is invalid or has expired",
+			})
+			return
+		}
+
+		log.Println("UnlockAccount error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to unlock account",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Account unlocked, you can log in again",
+	})
+}
+
+// respondTokens signs an access token for the user and sends it with the refresh token.
+func (uc *UserController) respondTokens(c *gin.Context, user *models.User, refreshToken, message string) {
 	claim := pkg.NewJWTClaims(user.ID, user.Email, string(user.Role))
 	token, err := claim.GenerateToken()
 	if err != nil {
@@ -96,19 +280,151 @@ func (uc *UserController) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, dtos.Response{
 		Code:    http.StatusOK,
 		Success: true,
-		Message: "Login Successfully",
+		Message: message,
 		Data: dtos.UserResponse{
-			UserID: claim.UserID,
-			Email:  claim.Email,
-			Role:   claim.Role,
-			Token:  token,
+			UserID:       claim.UserID,
+			Email:        claim.Email,
+			Role:         claim.Role,
+			Token:        token,
+			ExpiresAt:    claim.ExpiresAt.Time,
+			RefreshToken: refreshToken,
 		},
 	})
 }
 
+// Refresh godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token and a new refresh token. Each refresh token works once; presenting a used one ends the whole session.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dtos.RefreshTokenRequest true "Refresh token from login or the previous refresh"
+// @Success 200 {object} dtos.Response{data=dtos.UserResponse}
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 401 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /auth/refresh [post]
+func (uc *UserController) Refresh(c *gin.Context) {
+	var body dtos.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid Request Body",
+		})
+		return
+	}
+
+	user, refreshToken, err := uc.refreshTokens.Rotate(c.Request.Context(), body.RefreshToken)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenReused) {
+			log.Println("Refresh token reuse detected, session revoked")
+		}
+		if errors.Is(err, repositories.ErrRefreshTokenInvalid) || errors.Is(err, repositories.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, dtos.Response{
+				Code:    http.StatusUnauthorized,
+				Success: false,
+				Message: "Please log in again",
+			})
+			return
+		}
+
+		log.Println("Rotate refresh token error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to Generate Token",
+		})
+		return
+	}
+
+	uc.respondTokens(c, user, refreshToken, "Token refreshed")
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revoke the access token used for this request and, when given, the session of the refresh token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dtos.LogoutRequest false "Refresh token to revoke"
+// @Success 200 {object} dtos.Response
+// @Failure 401 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /auth/logout [post]
+func (uc *UserController) Logout(c *gin.Context) {
+	claims, ok := c.Get("claims")
+	access, isClaims := claims.(*pkg.Claims)
+	if !ok || !isClaims {
+		c.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	var body dtos.LogoutRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: "Invalid Request Body",
+			})
+			return
+		}
+	}
+
+	err := uc.denylist.Deny(c.Request.Context(), access.ID, access.ExpiresAt.Time)
+	if err == nil && body.RefreshToken != "" {
+		err = uc.refreshTokens.Revoke(c.Request.Context(), access.UserID, body.RefreshToken)
+	}
+	if err != nil {
+		log.Println("Logout error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to log out",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Logout Successfully",
+	})
+}
+
+// GetJWKS godoc
+// @Summary JSON Web Key Set
+// @Description Public keys access tokens are signed with, by kid. Keys being rotated out stay listed until they retire. The set is returned as-is, not wrapped in a response envelope.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} pkg.JWKS
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /.well-known/jwks.json [get]
+func (uc *UserController) GetJWKS(c *gin.Context) {
+	keyring, err := pkg.CurrentKeyring()
+	if err != nil {
+		log.Println("Keyring error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to get keys",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, keyring.JWKS())
+}
+
 // Register godoc
 // @Summary User registration
-// @Description Register a new user with email and password
+// @Description Register a new user with email and password. A link to verify the email is mailed to it.
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -159,6 +475,10 @@ func (uc *UserController) Register(c *gin.Context) {
 		return
 	}
 
+	// Sent in the background so a slow mail server does not hold up registering; a lost
+	// email can be sent again.
+	go uc.sendVerification(context.WithoutCancel(c.Request.Context()), &user)
+
 	c.JSON(http.StatusCreated, dtos.Response{
 		Code:    http.StatusCreated,
 		Success: true,
@@ -167,6 +487,266 @@ func (uc *UserController) Register(c *gin.Context) {
 	})
 }
 
+func (uc *UserController) sendVerification(ctx context.Context, user *models.User) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	token, wait, err := uc.userTokens.IssueAfterCooldown(ctx, user.ID, models.UserTokenVerifyEmail,
+		uc.verifyTTL, uc.verifyResendInterval, uc.verifyResendPerHour)
+	if err != nil {
+		log.Println("Verification token error:", err)
+		return
+	}
+	if wait > 0 {
+		log.Printf("Verification email for user %s throttled for %s\n", user.ID, wait.Round(time.Second))
+		return
+	}
+
+	link := utils.GetBaseURL() + "/auth/verify?token=" + url.QueryEscape(token)
+	msg, err := mailer.VerificationEmail(user.Email, link, uc.verifyTTL)
+	if err == nil {
+		err = uc.transport.Send(ctx, msg)
+	}
+	if err != nil {
+		log.Println("Verification email error:", err)
+	}
+}
+
+// VerifyEmail godoc
+// @Summary Verify email
+// @Description Confirm the email of an account with the token from the link mailed at registration. Each token works once.
+// @Tags Auth
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /auth/verify [get]
+func (uc *UserController) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Token is required",
+		})
+		return
+	}
+
+	if err := uc.userTokens.VerifyEmail(c.Request.Context(), token); err != nil {
+		if errors.Is(err, repositories.ErrUserTokenInvalid) {
+			c.JSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: "Verification link is invalid or has expired",
+			})
+			return
+		}
+
+		log.Println("VerifyEmail error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to verify email",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Email verified",
+	})
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Mail a new verification link. The response is the same whether or not the account exists or is already verified, and requests beyond VERIFY_RESEND_INTERVAL and VERIFY_RESEND_PER_HOUR are dropped silently.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dtos.ResendVerificationRequest true "Email of the account"
+// @Success 202 {object} dtos.Response
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /auth/verify/resend [post]
+func (uc *UserController) ResendVerification(c *gin.Context) {
+	var body dtos.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid Request Body",
+		})
+		return
+	}
+
+	user, err := uc.userRepository.GetEmail(c.Request.Context(), body.Email)
+	if err != nil && !errors.Is(err, repositories.ErrUserNotFound) {
+		log.Println("GetEmail error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to send verification email",
+		})
+		return
+	}
+	if user != nil && user.VerifiedAt == nil {
+		// Sent in the background, like password resets, so neither the response nor its
+		// timing tells whether the account exists.
+		go uc.sendVerification(context.WithoutCancel(c.Request.Context()), user)
+	}
+
+	c.JSON(http.StatusAccepted, dtos.Response{
+		Code:    http.StatusAccepted,
+		Success: true,
+		Message: "If the account is waiting for verification, a new link is on its way",
+	})
+}
+
+// ForgotPassword godoc
+// @Summary Forgot password
+// @Description Mail a link for choosing a new password. The response is the same whether or not an account has the email, and requests beyond RESET_REQUEST_INTERVAL and RESET_REQUESTS_PER_HOUR are dropped silently.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dtos.ForgotPasswordRequest true "Email of the account"
+// @Success 202 {object} dtos.Response
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /auth/forgot-password [post]
+func (uc *UserController) ForgotPassword(c *gin.Context) {
+	var body dtos.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid Request Body",
+		})
+		return
+	}
+
+	user, err := uc.userRepository.GetEmail(c.Request.Context(), body.Email)
+	if err != nil && !errors.Is(err, repositories.ErrUserNotFound) {
+		log.Println("GetEmail error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to send reset email",
+		})
+		return
+	}
+	if user != nil {
+		// Sent in the background so the response takes as long whether or not the
+		// account exists.
+		go uc.sendPasswordReset(context.WithoutCancel(c.Request.Context()), user)
+	}
+
+	c.JSON(http.StatusAccepted, dtos.Response{
+		Code:    http.StatusAccepted,
+		Success: true,
+		Message: "If an account uses this email, a link to reset the password is on its way",
+	})
+}
+
+func (uc *UserController) sendPasswordReset(ctx context.Context, user *models.User) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	token, wait, err := uc.userTokens.IssueAfterCooldown(ctx, user.ID, models.UserTokenResetPassword,
+		uc.resetTTL, uc.resetInterval, uc.resetPerHour)
+	if err != nil {
+		log.Println("Reset token error:", err)
+		return
+	}
+	if wait > 0 {
+		log.Printf("Password reset for user %s throttled for %s\n", user.ID, wait.Round(time.Second))
+		return
+	}
+
+	link := uc.resetURL + "?token=" + url.QueryEscape(token)
+	msg, err := mailer.PasswordResetEmail(user.Email, link, uc.resetTTL)
+	if err == nil {
+		err = uc.transport.Send(ctx, msg)
+	}
+	if err != nil {
+		log.Println("Reset email error:", err)
+	}
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password with the token from the reset email. Each token works once, and using it signs the user out of every session and lifts a login lockout.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dtos.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} dtos.Response
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /auth/reset-password [post]
+func (uc *UserController) ResetPassword(c *gin.Context) {
+	var body dtos.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid Request Body",
+		})
+		return
+	}
+
+	hash := pkg.NewHashConfig()
+	hash.UseRecommended()
+	hashed, err := hash.GenHash(body.NewPassword)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to hash new password",
+		})
+		return
+	}
+
+	resetAt := time.Now()
+	userID, email, err := uc.userTokens.ResetPassword(c.Request.Context(), body.Token, hashed)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserTokenInvalid) {
+			c.JSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: "Reset link is invalid or has expired",
+			})
+			return
+		}
+
+		log.Println("ResetPassword error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to reset password",
+		})
+		return
+	}
+
+	// The password is already changed and refresh tokens are revoked; access tokens
+	// left valid by a failure here run out within the hour.
+	if err := uc.denylist.RevokeUser(c.Request.Context(), userID, resetAt); err != nil {
+		log.Println("RevokeUser error:", err)
+	}
+	// Using the reset link proves control of the email, as the unlock link would.
+	if err := uc.loginGuard.Unlock(c.Request.Context(), email); err != nil {
+		log.Println("Login guard error:", err)
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Password has been reset, please log in again",
+	})
+}
+
 // GetProfile godoc
 // @Summary Get user profile
 // @Description Retrieve logged in user profile
@@ -439,3 +1019,111 @@ func (uc *UserController) ChangeAvatar(c *gin.Context) {
 		Data:    nil,
 	})
 }
+
+// GetNotificationPreferences godoc
+// @Summary Get notification preferences
+// @Description Channels showtime reminders are sent on and how many hours before the show
+// @Tags Profile
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dtos.Response{data=models.NotificationPreferences}
+// @Failure 401 {object} dtos.ErrResponse
+// @Failure 404 {object} dtos.ErrResponse
+// @Router /profile/notifications [get]
+func (uc *UserController) GetNotificationPreferences(c *gin.Context) {
+	user, err := utils.GetUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Unauthorized: " + err.Error(),
+		})
+		return
+	}
+
+	prefs, err := uc.userRepository.GetNotificationPreferences(c.Request.Context(), user.ID)
+	if err != nil {
+		log.Println("GetNotificationPreferences error:", err)
+		c.JSON(http.StatusNotFound, dtos.Response{
+			Code:    http.StatusNotFound,
+			Success: false,
+			Message: "Profile not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Success",
+		Data:    prefs,
+	})
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Update notification preferences
+// @Description Turn reminder channels on or off and set how many hours before the show they are sent. Reminders of upcoming bookings are rescheduled to match.
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dtos.NotificationPreferencesRequest true "Fields to change"
+// @Success 200 {object} dtos.Response{data=models.NotificationPreferences}
+// @Failure 400 {object} dtos.ErrResponse
+// @Failure 401 {object} dtos.ErrResponse
+// @Failure 500 {object} dtos.ErrResponse
+// @Router /profile/notifications [patch]
+func (uc *UserController) UpdateNotificationPreferences(c *gin.Context) {
+	user, err := utils.GetUser(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Unauthorized: " + err.Error(),
+		})
+		return
+	}
+
+	var req dtos.NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Code:    http.StatusBadRequest,
+			Success: false,
+			Message: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	prefs, err := uc.userRepository.GetNotificationPreferences(c.Request.Context(), user.ID)
+	if err == nil {
+		if req.Email != nil {
+			prefs.Email = *req.Email
+		}
+		if req.Push != nil {
+			prefs.Push = *req.Push
+		}
+		if req.SMS != nil {
+			prefs.SMS = *req.SMS
+		}
+		if req.ReminderHours != nil {
+			prefs.ReminderHours = *req.ReminderHours
+		}
+		err = uc.userRepository.UpdateNotificationPreferences(c.Request.Context(), user.ID, prefs)
+	}
+	if err != nil {
+		log.Println("UpdateNotificationPreferences error:", err)
+		c.JSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Failed to update notification preferences",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.Response{
+		Code:    http.StatusOK,
+		Success: true,
+		Message: "Notification preferences updated",
+		Data:    prefs,
+	})
+}
@@ -6,10 +6,11 @@ import (
 )
 
 type ScheduleRequest struct {
-	CinemaID   int    `json:"cinema_id" form:"cinema_id" example:"2"`
-	LocationID int    `json:"location_id" form:"location_id" example:"1"`
-	Date       string `json:"date" form:"date" example:"2025-12-01"`
-	TimeIDs    []int  `json:"time_ids" form:"time_ids" example:"1"`
+	CinemaID     int    `json:"cinema_id" form:"cinema_id" example:"2"`
+	LocationID   int    `json:"location_id" form:"location_id" example:"1"`
+	AuditoriumID int    `json:"auditorium_id" form:"auditorium_id" example:"3"`
+	Date         string `json:"date" form:"date" example:"2025-12-01"`
+	TimeIDs      []int  `json:"time_ids" form:"time_ids" example:"1"`
 }
 
 type CreateMovieRequest struct {
@@ -39,3 +40,7 @@ type UpdateMovieRequest struct {
 	Casts       []int                 `json:"casts" form:"casts" example:"[3,5,7]"`
 	Schedules   []ScheduleRequest     `json:"schedules" form:"-"`
 }
+
+type AssignStaffRequest struct {
+	CinemaID int `json:"cinema_id" binding:"required" example:"1"`
+}
@@ -0,0 +1,27 @@
+package dtos
+
+type AuditoriumSeatRequest struct {
+	Row       int    `json:"row" binding:"required,min=1" example:"1"`
+	Column    int    `json:"column" binding:"required,min=1" example:"3"`
+	SeatClass string `json:"seat_class" example:"regular"`
+	SeatCode  string `json:"seat_code" example:"A3"`
+}
+
+type CreateAuditoriumRequest struct {
+	CinemaID   int                     `json:"cinema_id" binding:"required" example:"1"`
+	LocationID int                     `json:"location_id" binding:"required" example:"2"`
+	Name       string                  `json:"name" binding:"required,max=50" example:"Studio 1"`
+	Rows       int                     `json:"rows" binding:"required,min=1,max=52" example:"8"`
+	Columns    int                     `json:"columns" binding:"required,min=1,max=60" example:"14"`
+	Seats      []AuditoriumSeatRequest `json:"seats" binding:"dive"`
+}
+
+type UpdateAuditoriumRequest struct {
+	Name string `json:"name" binding:"required,max=50" example:"IMAX"`
+}
+
+type AuditoriumLayoutRequest struct {
+	Rows    int                     `json:"rows" binding:"required,min=1,max=52" example:"8"`
+	Columns int                     `json:"columns" binding:"required,min=1,max=60" example:"14"`
+	Seats   []AuditoriumSeatRequest `json:"seats" binding:"required,min=1,dive"`
+}
@@ -0,0 +1,6 @@
+package dtos
+
+type CheckinRequest struct {
+	Ticket    string   `json:"ticket" binding:"required" example:"TKT1.eyJvaWQiOjEyfQ.c2lnbmF0dXJl"`
+	SeatCodes []string `json:"seat_codes" example:"[\"A1\"]"`
+}
@@ -7,4 +7,38 @@ type CreateOrderRequest struct {
 	Email      string   `json:"email" binding:"required,email" example:"farid@example.com"`
 	Phone      string   `json:"phone" binding:"required" example:"+628123456789"`
 	SeatCodes  []string `json:"seat_codes" binding:"required,min=1" example:"[\"A1\",\"A2\"]"`
+	Points     int      `json:"points" binding:"min=0" example:"100"`
+	PromoCode  string   `json:"promo_code" example:"HEMAT20"`
+}
+
+type SeatHoldRequest struct {
+	ScheduleID int      `json:"schedule_id" binding:"required" example:"8"`
+	SeatCodes  []string `json:"seat_codes" binding:"required,min=1" example:"[\"A1\",\"A2\"]"`
+}
+
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=pending paid checked_in expired refunded cancelled" example:"paid"`
+	Note   string `json:"note" example:"Paid at the counter"`
+}
+
+type PriceQuoteRequest struct {
+	ScheduleID int      `json:"schedule_id" binding:"required" example:"8"`
+	SeatCodes  []string `json:"seat_codes" binding:"required,min=1" example:"[\"A1\",\"A2\"]"`
+}
+
+type CancelOrderRequest struct {
+	SeatCodes []string `json:"seat_codes" example:"[\"A2\"]"`
+	Reason    string   `json:"reason" example:"Cannot make it"`
+}
+
+type AdminCancelOrderRequest struct {
+	SeatCodes []string `json:"seat_codes" example:"[\"A2\"]"`
+	Reason    string   `json:"reason" binding:"required" example:"Screening cancelled"`
+}
+
+type ValidatePromoRequest struct {
+	Code       string   `json:"code" binding:"required" example:"HEMAT20"`
+	ScheduleID int      `json:"schedule_id" binding:"required" example:"8"`
+	PaymentID  int      `json:"payment_id" example:"2"`
+	SeatCodes  []string `json:"seat_codes" binding:"required,min=1" example:"[\"A1\",\"A2\"]"`
 }
@@ -0,0 +1,25 @@
+package dtos
+
+import "time"
+
+// PromoRules are the editable settings of a promo code. Empty id lists and missing
+// limits leave the promo unrestricted.
+type PromoRules struct {
+	Description   *string    `json:"description" example:"20% off for two tickets or more"`
+	DiscountType  string     `json:"discount_type" binding:"required,oneof=percent fixed" example:"percent"`
+	DiscountValue int64      `json:"discount_value" binding:"required,min=1" example:"20"`
+	MaxDiscount   *int64     `json:"max_discount" binding:"omitempty,min=1" example:"50000"`
+	MinTickets    int        `json:"min_tickets" binding:"min=0" example:"2"`
+	StartsAt      *time.Time `json:"starts_at" example:"2025-12-01T00:00:00Z"`
+	EndsAt        *time.Time `json:"ends_at" example:"2025-12-31T23:59:59Z"`
+	MovieIDs      []int      `json:"movie_ids" example:"1,2"`
+	CinemaIDs     []int      `json:"cinema_ids" example:"3"`
+	PaymentIDs    []int      `json:"payment_ids" example:"2"`
+	UsageLimit    *int       `json:"usage_limit" binding:"omitempty,min=1" example:"500"`
+	PerUserLimit  *int       `json:"per_user_limit" binding:"omitempty,min=1" example:"1"`
+}
+
+type CreatePromoRequest struct {
+	Code string `json:"code" binding:"required,max=32,alphanum" example:"HEMAT20"`
+	PromoRules
+}
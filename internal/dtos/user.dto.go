@@ -2,6 +2,7 @@ package dtos
 
 import (
 	"mime/multipart"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -21,16 +22,39 @@ type AvatarProfileRequest struct {
 	Avatar *multipart.FileHeader `form:"avatar"`
 }
 
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email" example:"user@mail.com"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" example:"user@mail.com"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required" example:"Password123"`
+}
+
 type ChangePasswordRequest struct {
 	OldPassword string `form:"old_password" json:"old_password" binding:"required"`
 	NewPassword string `form:"new_password" json:"new_password" binding:"required"`
 }
 
 type UserResponse struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
-	Role   string    `json:"role"`
-	Token  string    `json:"token"`
+	UserID       uuid.UUID `json:"user_id"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	Token        string    `json:"token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 type ProfileResponse struct {
@@ -41,3 +65,15 @@ type ProfileResponse struct {
 	Avatar      *string   `json:"avatar" example:"https://example.com/avatar.png"`
 	Point       *int      `json:"point" example:"100"`
 }
+
+type CalendarFeedResponse struct {
+	FeedURL   string `json:"feed_url" example:"https://api.tickitz.id/calendar/Zm9vYmFy/bookings.ics"`
+	WebcalURL string `json:"webcal_url" example:"webcal://api.tickitz.id/calendar/Zm9vYmFy/bookings.ics"`
+}
+
+type NotificationPreferencesRequest struct {
+	Email         *bool `json:"email" example:"true"`
+	Push          *bool `json:"push" example:"false"`
+	SMS           *bool `json:"sms" example:"true"`
+	ReminderHours *int  `json:"reminder_hours" binding:"omitempty,min=1,max=72" example:"3"`
+}
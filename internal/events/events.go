@@ -0,0 +1,38 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Channel is the Redis pub/sub channel every domain event is published on.
+const Channel = "tickitz:events"
+
+const (
+	OrderExpired = "order.expired"
+)
+
+type Event struct {
+	Type string    `json:"type"`
+	At   time.Time `json:"at"`
+	Data any       `json:"data"`
+}
+
+type Publisher struct {
+	rdb *redis.Client
+}
+
+func NewPublisher(rdb *redis.Client) *Publisher {
+	return &Publisher{rdb: rdb}
+}
+
+func (p *Publisher) Publish(ctx context.Context, eventType string, data any) error {
+	payload, err := json.Marshal(Event{Type: eventType, At: time.Now(), Data: data})
+	if err != nil {
+		return err
+	}
+	return p.rdb.Publish(ctx, Channel, payload).Err()
+}
@@ -0,0 +1,120 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"time"
+)
+
+// Inline is an image shown in the HTML body through "cid:<ContentID>".
+type Inline struct {
+	ContentID   string
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	Inlines []Inline
+}
+
+type Transport interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Bytes renders the message as MIME: a multipart/related body holding the text and
+// HTML alternatives followed by the inline images.
+func (m *Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	related := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", m.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", m.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/related; boundary=%s\r\n\r\n", related.Boundary())
+
+	var alt bytes.Buffer
+	alternative := multipart.NewWriter(&alt)
+	if err := writeQuotedPrintable(alternative, "text/plain; charset=utf-8", m.Text); err != nil {
+		return nil, err
+	}
+	if err := writeQuotedPrintable(alternative, "text/html; charset=utf-8", m.HTML); err != nil {
+		return nil, err
+	}
+	if err := alternative.Close(); err != nil {
+		return nil, err
+	}
+
+	part, err := related.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + alternative.Boundary()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(alt.Bytes()); err != nil {
+		return nil, err
+	}
+
+	for _, inline := range m.Inlines {
+		part, err := related.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {inline.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-ID":                {"<" + inline.ContentID + ">"},
+			"Content-Disposition":       {`inline; filename="` + inline.Filename + `"`},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(part, inline.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := related.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w *multipart.Writer, contentType, body string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// writeBase64 wraps the encoded data at 76 characters as MIME requires.
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := w.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := w.Write([]byte(encoded + "\r\n"))
+	return err
+}
@@ -0,0 +1,49 @@
+package mailer
+
+import (
+	"bytes"
+	"html/template"
+	texttemplate "text/template"
+	"time"
+)
+
+var resetHTML = template.Must(template.New("reset.html").Parse(`<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f5f6f8;font-family:Arial,Helvetica,sans-serif;color:#14142b">
+<div style="max-width:560px;margin:0 auto;background:#ffffff;border-radius:8px;padding:24px">
+  <h2 style="margin:0 0 12px">Reset your password</h2>
+  <p style="margin:0 0 16px">Someone asked to reset the password of your Tickitz account. Choose a new one with the button below.</p>
+  <p style="margin:0 0 16px"><a href="{{.Link}}" style="display:inline-block;padding:12px 20px;background:#5f2eea;color:#ffffff;border-radius:6px;text-decoration:none">Reset password</a></p>
+  <p style="margin:0;color:#6e7191">The link expires in {{.ValidFor}} and signs you out everywhere once used. If you did not ask for it, you can ignore this email; your password stays the same.</p>
+</div>
+</body>
+</html>
+`))
+
+var resetText = texttemplate.Must(texttemplate.New("reset.txt").Parse(`Someone asked to reset the password of your Tickitz account. Choose a new one here:
+{{.Link}}
+
+The link expires in {{.ValidFor}} and signs you out everywhere once used. If you did not ask for it, you can ignore this email; your password stays the same.
+
+Tickitz
+`))
+
+// PasswordResetEmail sends to the link for choosing a new password, valid for ttl.
+func PasswordResetEmail(to, link string, ttl time.Duration) (*Message, error) {
+	data := linkData{Link: link, ValidFor: validFor(ttl)}
+
+	var html, text bytes.Buffer
+	if err := resetHTML.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	if err := resetText.Execute(&text, data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:      to,
+		Subject: "Reset your Tickitz password",
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
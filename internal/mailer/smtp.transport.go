@@ -0,0 +1,108 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"time"
+)
+
+// SMTPTransport sends mail through an SMTP server. It upgrades to TLS when the server
+// offers STARTTLS and authenticates only when a username is set, so it also works
+// against a local catcher such as MailHog on port 1025.
+type SMTPTransport struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPTransport() *SMTPTransport {
+	t := &SMTPTransport{
+		host:     os.Getenv("SMTP_HOST"),
+		port:     os.Getenv("SMTP_PORT"),
+		username: os.Getenv("SMTP_USERNAME"),
+		password: os.Getenv("SMTP_PASSWORD"),
+		from:     os.Getenv("SMTP_FROM"),
+	}
+	if t.host == "" {
+		t.host = "localhost"
+	}
+	if t.port == "" {
+		t.port = "1025"
+	}
+	if t.from == "" {
+		t.from = "Tickitz <no-reply@tickitz.local>"
+	}
+	return t
+}
+
+// Send delivers msg, sending it from SMTP_FROM unless msg.From is set.
+func (t *SMTPTransport) Send(ctx context.Context, msg *Message) error {
+	if msg.From == "" {
+		msg.From = t.from
+	}
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return err
+	}
+
+	raw, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(t.host, t.port))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, t.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: t.host}); err != nil {
+			return err
+		}
+	}
+	if t.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", t.username, t.password, t.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
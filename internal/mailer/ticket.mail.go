@@ -0,0 +1,139 @@
+package mailer
+
+import (
+	"bytes"
+	"html/template"
+	"net/mail"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/Darari17/be-tickitz-full/pkg"
+)
+
+const ticketQRContentID = "ticket-qr"
+
+var ticketFuncs = map[string]any{
+	"rupiah": utils.FormatRupiah,
+	"hhmm":   utils.FormatShowTime,
+}
+
+var ticketHTML = template.Must(template.New("ticket.html").Funcs(ticketFuncs).Parse(`<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f5f6f8;font-family:Arial,Helvetica,sans-serif;color:#14142b">
+<table role="presentation" width="100%" style="max-width:560px;margin:0 auto;background:#ffffff;border-radius:8px;padding:24px">
+  <tr><td>
+    <h2 style="margin:0 0 4px">Your ticket is ready</h2>
+    <p style="margin:0 0 16px;color:#6e7191">Hi {{.Order.FullName}}, thanks for booking with Tickitz.</p>
+    <table role="presentation" width="100%">
+      <tr>
+        {{if .PosterURL}}<td width="120" valign="top"><img src="{{.PosterURL}}" alt="{{.Order.Movie.Title}}" width="110" style="border-radius:6px"></td>{{end}}
+        <td valign="top">
+          <h3 style="margin:0 0 8px">{{.Order.Movie.Title}}</h3>
+          <p style="margin:0 0 4px">{{.Order.CinemaName}}, {{.Order.Location}}</p>
+          <p style="margin:0 0 4px">{{.Order.Date.Format "Monday, 2 January 2006"}} at {{hhmm .Order.TimeStr}}</p>
+          <p style="margin:0">Seats: <strong>{{.SeatList}}</strong></p>
+        </td>
+      </tr>
+    </table>
+    <div style="text-align:center;margin:24px 0">
+      <img src="cid:{{.QRContentID}}" alt="Ticket QR code" width="220" height="220">
+      <p style="margin:8px 0 0;color:#6e7191">Booking reference <strong>{{.Order.Reference}}</strong><br>Show this code at the entrance.</p>
+    </div>
+    <h3 style="margin:0 0 8px">Receipt</h3>
+    <table role="presentation" width="100%" style="border-collapse:collapse">
+      {{range .Order.ActiveSeats}}<tr><td style="padding:4px 0">Seat {{.SeatCode}} ({{.SeatClass}})</td><td align="right">{{rupiah .Price}}</td></tr>
+      {{end}}<tr><td style="padding:4px 0;border-top:1px solid #dedede">Subtotal</td><td align="right" style="border-top:1px solid #dedede">{{rupiah .Order.Subtotal}}</td></tr>
+      <tr><td style="padding:4px 0">Fees</td><td align="right">{{rupiah .Order.Fees}}</td></tr>
+      {{if .Order.Discount}}<tr><td style="padding:4px 0">Discount</td><td align="right">-{{rupiah .Order.Discount}}</td></tr>
+      {{end}}<tr><td style="padding:4px 0;font-weight:bold">Total paid via {{.Order.PaymentName}}</td><td align="right" style="font-weight:bold">{{rupiah .Order.Total}}</td></tr>
+    </table>
+  </td></tr>
+</table>
+</body>
+</html>
+`))
+
+var ticketText = texttemplate.Must(texttemplate.New("ticket.txt").Funcs(ticketFuncs).Parse(`Hi {{.Order.FullName}},
+
+Your ticket is ready.
+
+{{.Order.Movie.Title}}
+{{.Order.CinemaName}}, {{.Order.Location}}
+{{.Order.Date.Format "Monday, 2 January 2006"}} at {{hhmm .Order.TimeStr}}
+Seats: {{.SeatList}}
+
+Booking reference: {{.Order.Reference}}
+Show the QR code in this email at the entrance. Ticket code:
+{{.Order.QRCode}}
+
+Receipt
+{{range .Order.ActiveSeats}}  Seat {{.SeatCode}} ({{.SeatClass}})  {{rupiah .Price}}
+{{end}}  Subtotal  {{rupiah .Order.Subtotal}}
+  Fees      {{rupiah .Order.Fees}}
+{{if .Order.Discount}}  Discount  -{{rupiah .Order.Discount}}
+{{end}}  Total     {{rupiah .Order.Total}} ({{.Order.PaymentName}})
+
+Tickitz
+`))
+
+type ticketData struct {
+	Order       *models.OrderDetail
+	PosterURL   string
+	SeatList    string
+	QRContentID string
+}
+
+// TicketEmail renders the e-ticket and receipt of a paid order. Posters uploaded to this
+// server are linked through baseURL.
+func TicketEmail(order *models.OrderDetail, baseURL string) (*Message, error) {
+	qr, err := pkg.NewQRCode(order.QRCode)
+	if err != nil {
+		return nil, err
+	}
+	png, err := qr.PNG(6)
+	if err != nil {
+		return nil, err
+	}
+
+	seats := make([]string, 0, len(order.Seats))
+	for _, s := range order.ActiveSeats() {
+		seats = append(seats, s.SeatCode)
+	}
+
+	data := ticketData{
+		Order:       order,
+		PosterURL:   posterURL(order.Movie.Poster, baseURL),
+		SeatList:    strings.Join(seats, ", "),
+		QRContentID: ticketQRContentID,
+	}
+
+	var html, text bytes.Buffer
+	if err := ticketHTML.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	if err := ticketText.Execute(&text, data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:      (&mail.Address{Name: order.FullName, Address: order.Email}).String(),
+		Subject: "Your Tickitz ticket for " + order.Movie.Title + " (" + order.Reference + ")",
+		Text:    text.String(),
+		HTML:    html.String(),
+		Inlines: []Inline{{
+			ContentID:   ticketQRContentID,
+			Filename:    "ticket-" + order.Reference + ".png",
+			ContentType: "image/png",
+			Data:        png,
+		}},
+	}, nil
+}
+
+func posterURL(poster, baseURL string) string {
+	if poster == "" || strings.HasPrefix(poster, "http://") || strings.HasPrefix(poster, "https://") {
+		return poster
+	}
+	return strings.TrimRight(baseURL, "/") + "/img/" + strings.TrimLeft(poster, "/")
+}
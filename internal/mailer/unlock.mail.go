@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"bytes"
+	"html/template"
+	texttemplate "text/template"
+	"time"
+)
+
+var unlockHTML = template.Must(template.New("unlock.html").Parse(`<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f5f6f8;font-family:Arial,Helvetica,sans-serif;color:#14142b">
+<div style="max-width:560px;margin:0 auto;background:#ffffff;border-radius:8px;padding:24px">
+  <h2 style="margin:0 0 12px">Your account was locked</h2>
+  <p style="margin:0 0 16px">There were too many failed attempts to log in to your Tickitz account, so logging in is paused for {{.ValidFor}}. If it was you, unlock it now with the button below.</p>
+  <p style="margin:0 0 16px"><a href="{{.Link}}" style="display:inline-block;padding:12px 20px;background:#5f2eea;color:#ffffff;border-radius:6px;text-decoration:none">Unlock account</a></p>
+  <p style="margin:0;color:#6e7191">If it was not you, someone may be guessing your password. Consider resetting it.</p>
+</div>
+</body>
+</html>
+`))
+
+var unlockText = texttemplate.Must(texttemplate.New("unlock.txt").Parse(`There were too many failed attempts to log in to your Tickitz account, so logging in is paused for {{.ValidFor}}. If it was you, unlock it now:
+{{.Link}}
+
+If it was not you, someone may be guessing your password. Consider resetting it.
+
+Tickitz
+`))
+
+// UnlockEmail tells the owner of to that the account was locked for lockedFor and
+// sends the link that lifts the lock.
+func UnlockEmail(to, link string, lockedFor time.Duration) (*Message, error) {
+	data := linkData{Link: link, ValidFor: validFor(lockedFor)}
+
+	var html, text bytes.Buffer
+	if err := unlockHTML.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	if err := unlockText.Execute(&text, data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:      to,
+		Subject: "Your Tickitz account was locked",
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
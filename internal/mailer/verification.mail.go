@@ -0,0 +1,69 @@
+package mailer
+
+import (
+	"bytes"
+	"html/template"
+	"strconv"
+	texttemplate "text/template"
+	"time"
+)
+
+var verificationHTML = template.Must(template.New("verification.html").Parse(`<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f5f6f8;font-family:Arial,Helvetica,sans-serif;color:#14142b">
+<div style="max-width:560px;margin:0 auto;background:#ffffff;border-radius:8px;padding:24px">
+  <h2 style="margin:0 0 12px">Confirm your email</h2>
+  <p style="margin:0 0 16px">Welcome to Tickitz! Confirm that this address belongs to you to finish setting up your account.</p>
+  <p style="margin:0 0 16px"><a href="{{.Link}}" style="display:inline-block;padding:12px 20px;background:#5f2eea;color:#ffffff;border-radius:6px;text-decoration:none">Verify email</a></p>
+  <p style="margin:0;color:#6e7191">The link expires in {{.ValidFor}}. If you did not sign up, you can ignore this email.</p>
+</div>
+</body>
+</html>
+`))
+
+var verificationText = texttemplate.Must(texttemplate.New("verification.txt").Parse(`Welcome to Tickitz!
+
+Confirm that this address belongs to you to finish setting up your account:
+{{.Link}}
+
+The link expires in {{.ValidFor}}. If you did not sign up, you can ignore this email.
+
+Tickitz
+`))
+
+type linkData struct {
+	Link     string
+	ValidFor string
+}
+
+// VerificationEmail asks the owner of to to open link, which stays valid for ttl.
+func VerificationEmail(to, link string, ttl time.Duration) (*Message, error) {
+	data := linkData{Link: link, ValidFor: validFor(ttl)}
+
+	var html, text bytes.Buffer
+	if err := verificationHTML.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	if err := verificationText.Execute(&text, data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:      to,
+		Subject: "Verify your Tickitz email",
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+// validFor words a link lifetime as whole hours or minutes.
+func validFor(ttl time.Duration) string {
+	unit, n := "minute", int(ttl/time.Minute)
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		unit, n = "hour", int(ttl/time.Hour)
+	}
+	if n == 1 {
+		return "1 " + unit
+	}
+	return strconv.Itoa(n) + " " + unit + "s"
+}
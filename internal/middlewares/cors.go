@@ -24,7 +24,8 @@ func CORSMiddleware(ctx *gin.Context) {
 	}
 
 	ctx.Header("Access-Control-Allow-Methods", "GET, POST, PATCH, PUT, DELETE, OPTIONS")
-	ctx.Header("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept, Origin, X-Requested-With")
+	ctx.Header("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept, Origin, X-Requested-With, Idempotency-Key")
+	ctx.Header("Access-Control-Expose-Headers", "RateLimit-Policy, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, Retry-After")
 	ctx.Header("Access-Control-Allow-Credentials", "true")
 
 	if ctx.Request.Method == http.MethodOptions {
@@ -0,0 +1,179 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/dtos"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	idempotencyCommitted = "idempotency_committed"
+)
+
+type idempotentResponse struct {
+	Done        bool   `json:"done"`
+	BodyHash    string `json:"body_hash"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// Idempotency replays the stored response when a client repeats a request with the same
+// Idempotency-Key header and body. Keys are scoped per user and route and kept for
+// IDEMPOTENCY_TTL. While the first request runs the key is only locked for
+// IDEMPOTENCY_LOCK_TTL, so a request that never finishes does not block retries for long.
+// It must run after RequiredToken; requests without the header pass through.
+func Idempotency(rdb *redis.Client) func(*gin.Context) {
+	ttl := utils.GetEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour)
+	lockTTL := utils.GetEnvDuration("IDEMPOTENCY_LOCK_TTL", time.Minute)
+
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+
+		if len(key) > 255 {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: "Idempotency-Key must be at most 255 characters",
+			})
+			return
+		}
+
+		user, err := utils.GetUser(ctx)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dtos.Response{
+				Code:    http.StatusUnauthorized,
+				Success: false,
+				Message: "Authentication required",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, dtos.Response{
+				Code:    http.StatusBadRequest,
+				Success: false,
+				Message: "Invalid request body",
+			})
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(body)
+		bodyHash := hex.EncodeToString(sum[:])
+		redisKey := "idempotency:" + user.ID.String() + ":" + ctx.Request.Method + ":" + ctx.Request.URL.Path + ":" + key
+
+		pending, _ := json.Marshal(idempotentResponse{BodyHash: bodyHash})
+		claimed, err := rdb.SetNX(ctx.Request.Context(), redisKey, pending, lockTTL).Result()
+		if err != nil {
+			log.Println("Idempotency Redis Error\nCause:", err.Error())
+			ctx.Next()
+			return
+		}
+
+		if !claimed {
+			replayIdempotent(ctx, rdb, redisKey, bodyHash)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		// The request context is cancelled once the client hangs up, but the outcome
+		// still has to be stored for its retry.
+		storeCtx := context.WithoutCancel(ctx.Request.Context())
+
+		// Server errors before anything was committed are not stored so the client can
+		// retry with the same key. Once the handler committed, a retry would run it twice.
+		if recorder.Status() >= http.StatusInternalServerError && !ctx.GetBool(idempotencyCommitted) {
+			if err := rdb.Del(storeCtx, redisKey).Err(); err != nil {
+				log.Println("Idempotency Redis Error\nCause:", err.Error())
+			}
+			return
+		}
+
+		done, _ := json.Marshal(idempotentResponse{
+			Done:        true,
+			BodyHash:    bodyHash,
+			Status:      recorder.Status(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err := rdb.Set(storeCtx, redisKey, done, ttl).Err(); err != nil {
+			log.Println("Idempotency Redis Error\nCause:", err.Error())
+		}
+	}
+}
+
+// MarkCommitted tells Idempotency that the handler's changes are stored, so its response
+// is kept for retries even if the handler fails afterwards.
+func MarkCommitted(ctx *gin.Context) {
+	ctx.Set(idempotencyCommitted, true)
+}
+
+func replayIdempotent(ctx *gin.Context, rdb *redis.Client, redisKey, bodyHash string) {
+	var stored idempotentResponse
+	if found, _ := utils.GetRedis(ctx.Request.Context(), rdb, redisKey, &stored); !found {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dtos.Response{
+			Code:    http.StatusConflict,
+			Success: false,
+			Message: "Request with this Idempotency-Key could not be replayed, retry later",
+		})
+		return
+	}
+
+	if stored.BodyHash != bodyHash {
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, dtos.Response{
+			Code:    http.StatusUnprocessableEntity,
+			Success: false,
+			Message: "Idempotency-Key was already used with a different request body",
+		})
+		return
+	}
+
+	if !stored.Done {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dtos.Response{
+			Code:    http.StatusConflict,
+			Success: false,
+			Message: "Request with this Idempotency-Key is still being processed",
+		})
+		return
+	}
+
+	ctx.Header("Idempotent-Replayed", "true")
+	ctx.Data(stored.Status, stored.ContentType, stored.Body)
+	ctx.Abort()
+}
@@ -0,0 +1,127 @@
+package middlewares
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/dtos"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/Darari17/be-tickitz-full/pkg"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// token bucket as GCRA: KEYS[1] holds the time the bucket is full again. ARGV are now,
+// the ms one request refills and the ms a full bucket takes. Returns {allowed, remaining,
+// ms until full, ms until the next request is allowed}.
+var rateLimitScript = redis.NewScript(`
+local now, interval, window = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local next_tat = tat + interval
+if next_tat - window > now then
+	return {0, 0, tat - now, next_tat - window - now}
+end
+redis.call('SET', KEYS[1], next_tat, 'PX', next_tat - now)
+return {1, math.floor((window - (next_tat - now)) / interval), next_tat - now, 0}
+`)
+
+// RateLimitPolicy is a budget of Limit requests per Window, shared by every route the
+// middleware built from it is attached to. RATE_LIMIT_<NAME>, e.g. RATE_LIMIT_SEATS=30/1m,
+// overrides it. With FailClosed set, requests are refused while the budget cannot be
+// checked instead of let through.
+type RateLimitPolicy struct {
+	Name       string
+	Limit      int
+	Window     time.Duration
+	FailClosed bool
+}
+
+func (p RateLimitPolicy) fromEnv() RateLimitPolicy {
+	key := "RATE_LIMIT_" + strings.ToUpper(p.Name)
+	value := os.Getenv(key)
+	if value == "" {
+		return p
+	}
+
+	limit, window, ok := strings.Cut(value, "/")
+	n, err := strconv.Atoi(limit)
+	d, err2 := time.ParseDuration(window)
+	if !ok || err != nil || err2 != nil || n <= 0 || d <= 0 {
+		log.Printf("Invalid %s %q, using %d/%s\n", key, value, p.Limit, p.Window)
+		return p
+	}
+	p.Limit, p.Window = n, d
+	return p
+}
+
+// RateLimit allows each client policy.Limit requests per policy.Window, refilled
+// evenly, and answers 429 with Retry-After beyond that. Clients are told their budget
+// in RateLimit-* headers. After RequiredToken the budget is per user, otherwise per
+// client address. If Redis is unavailable requests are let through, unless the policy
+// fails closed.
+func RateLimit(rdb *redis.Client, policy RateLimitPolicy) gin.HandlerFunc {
+	if !utils.GetEnvBool("RATE_LIMIT_ENABLED", true) {
+		return func(ctx *gin.Context) {
+			ctx.Next()
+		}
+	}
+
+	policy = policy.fromEnv()
+	interval := max(policy.Window.Milliseconds()/int64(policy.Limit), 1)
+	window := interval * int64(policy.Limit)
+	policyHeader := fmt.Sprintf("%d;w=%d", policy.Limit, int(policy.Window.Seconds()))
+
+	return func(ctx *gin.Context) {
+		client := "ip:" + ctx.ClientIP()
+		if value, ok := ctx.Get("claims"); ok {
+			if claims, ok := value.(*pkg.Claims); ok {
+				client = "user:" + claims.UserID.String()
+			}
+		}
+
+		result, err := rateLimitScript.Run(ctx.Request.Context(), rdb, []string{"ratelimit:" + policy.Name + ":" + client},
+			time.Now().UnixMilli(), interval, window).Int64Slice()
+		if err != nil {
+			log.Println("Rate limit error.\nCause: ", err.Error())
+			if policy.FailClosed {
+				ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, dtos.Response{
+					Code:    http.StatusServiceUnavailable,
+					Success: false,
+					Message: "Service is temporarily unavailable, please try again later",
+				})
+				return
+			}
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("RateLimit-Policy", policyHeader)
+		ctx.Header("RateLimit-Limit", strconv.Itoa(policy.Limit))
+		ctx.Header("RateLimit-Remaining", strconv.FormatInt(result[1], 10))
+		ctx.Header("RateLimit-Reset", strconv.FormatInt(ceilSeconds(result[2]), 10))
+
+		if result[0] == 0 {
+			ctx.Header("Retry-After", strconv.FormatInt(max(ceilSeconds(result[3]), 1), 10))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, dtos.Response{
+				Code:    http.StatusTooManyRequests,
+				Success: false,
+				Message: "Too many requests, please slow down",
+			})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func ceilSeconds(ms int64) int64 {
+	return (ms + 999) / 1000
+}
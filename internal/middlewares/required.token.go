@@ -1,17 +1,28 @@
 package middlewares
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/Darari17/be-tickitz-full/internal/dtos"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
 	"github.com/Darari17/be-tickitz-full/pkg"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func RequiredToken(ctx *gin.Context) {
+// RequiredToken authenticates the bearer access token and rejects tokens revoked
+// through the denylist.
+func RequiredToken(denylist *repositories.TokenDenylist) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requireToken(ctx, denylist)
+	}
+}
+
+func requireToken(ctx *gin.Context, denylist *repositories.TokenDenylist) {
 	bearerToken := ctx.GetHeader("Authorization")
 	if bearerToken == "" || !strings.HasPrefix(bearerToken, "Bearer ") {
 		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dtos.Response{
@@ -24,9 +35,6 @@ func RequiredToken(ctx *gin.Context) {
 
 	token := strings.TrimPrefix(bearerToken, "Bearer ")
 
-	// cek token redis
-	// ...
-
 	claims := &pkg.Claims{}
 
 	if err := claims.VerifyToken(token); err != nil {
@@ -50,6 +58,17 @@ func RequiredToken(ctx *gin.Context) {
 			return
 		}
 
+		// Tokens signed with a key that was rotated out, or not by us at all.
+		if errors.Is(err, jwt.ErrTokenMalformed) || errors.Is(err, jwt.ErrTokenUnverifiable) || errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+			log.Println("JWT Error.\nCause: ", err.Error())
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dtos.Response{
+				Code:    http.StatusUnauthorized,
+				Success: false,
+				Message: "Please log in again",
+			})
+			return
+		}
+
 		log.Println("Internal Server Error.\nCause: ", err.Error())
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dtos.Response{
 			Code:    http.StatusInternalServerError,
@@ -59,7 +78,29 @@ func RequiredToken(ctx *gin.Context) {
 		return
 	}
 
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	denied, err := denylist.IsDenied(ctx.Request.Context(), claims.ID, claims.UserID, issuedAt)
+	if err != nil {
+		log.Println("Token denylist error.\nCause: ", err.Error())
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dtos.Response{
+			Code:    http.StatusInternalServerError,
+			Success: false,
+			Message: "Internal Server Error",
+		})
+		return
+	}
+	if denied {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dtos.Response{
+			Code:    http.StatusUnauthorized,
+			Success: false,
+			Message: "Please log in again",
+		})
+		return
+	}
+
 	ctx.Set("claims", claims)
 	ctx.Next()
-
 }
@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Darari17/be-tickitz-full/internal/dtos"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/Darari17/be-tickitz-full/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+// Verified turns away users who have not verified their email, when
+// VERIFY_REQUIRED_FOR_ORDERS is set. It runs after RequiredToken.
+func Verified(ur *repositories.UserRepository) gin.HandlerFunc {
+	if !utils.GetEnvBool("VERIFY_REQUIRED_FOR_ORDERS", false) {
+		return func(ctx *gin.Context) {
+			ctx.Next()
+		}
+	}
+
+	return func(ctx *gin.Context) {
+		value, _ := ctx.Get("claims")
+		claims, ok := value.(*pkg.Claims)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, dtos.Response{
+				Code:    http.StatusInternalServerError,
+				Success: false,
+				Message: "Internal Server Error",
+			})
+			return
+		}
+
+		verified, err := ur.IsVerified(ctx.Request.Context(), claims.UserID)
+		if err != nil {
+			log.Println("IsVerified error.\nCause: ", err.Error())
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, dtos.Response{
+				Code:    http.StatusInternalServerError,
+				Success: false,
+				Message: "Internal Server Error",
+			})
+			return
+		}
+		if !verified {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, dtos.Response{
+				Code:    http.StatusForbidden,
+				Success: false,
+				Message: "Please verify your email before booking",
+			})
+			return
+		}
+
+		ctx.Next()
+	}
+}
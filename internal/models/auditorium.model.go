@@ -0,0 +1,176 @@
+package models
+
+import "time"
+
+type SeatStatus string
+
+const (
+	SeatAvailable SeatStatus = "available"
+	SeatSold      SeatStatus = "sold"
+	SeatHeld      SeatStatus = "held"
+	SeatGap       SeatStatus = "gap"
+)
+
+const (
+	SeatClassRegular    = "regular"
+	SeatClassPremium    = "premium"
+	SeatClassCouple     = "couple"
+	SeatClassWheelchair = "wheelchair"
+	SeatClassCompanion  = "companion"
+)
+
+// SeatClass is a category of seat. Its surcharge is added to the ticket price of every
+// seat of the class.
+type SeatClass struct {
+	Code      string `db:"code" json:"code"`
+	Name      string `db:"name" json:"name"`
+	Surcharge int64  `db:"surcharge" json:"surcharge"`
+}
+
+// Auditorium is a hall of a cinema at a location with its own seat map. Cells of the
+// rows x columns grid without a seat are aisles or gaps.
+type Auditorium struct {
+	ID         int              `db:"id" json:"id"`
+	CinemaID   *int             `db:"cinemas_id" json:"cinema_id"`
+	LocationID *int             `db:"locations_id" json:"location_id"`
+	Name       string           `db:"name" json:"name"`
+	Rows       int              `db:"row_count" json:"rows"`
+	Columns    int              `db:"column_count" json:"columns"`
+	CreatedAt  time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt  *time.Time       `db:"updated_at" json:"updated_at"`
+	Seats      []AuditoriumSeat `db:"-" json:"seats,omitempty"`
+}
+
+type AuditoriumSeat struct {
+	ID        int    `db:"id" json:"id"`
+	SeatCode  string `db:"seat_code" json:"seat_code"`
+	SeatClass string `db:"seat_class" json:"seat_class"`
+	Row       int    `db:"row_number" json:"row"`
+	Column    int    `db:"column_number" json:"column"`
+}
+
+type SeatLayout struct {
+	ScheduleID   int             `json:"schedule_id"`
+	AuditoriumID int             `json:"auditorium_id"`
+	Auditorium   string          `json:"auditorium"`
+	Rows         int             `json:"rows"`
+	Columns      int             `json:"columns"`
+	Classes      []SeatClass     `json:"classes"`
+	Grid         []SeatLayoutRow `json:"grid"`
+}
+
+type SeatLayoutRow struct {
+	Row   int              `json:"row"`
+	Label string           `json:"label"`
+	Cells []SeatLayoutCell `json:"cells"`
+}
+
+type SeatLayoutCell struct {
+	Column    int        `json:"column"`
+	SeatCode  string     `json:"seat_code,omitempty"`
+	SeatClass string     `json:"seat_class,omitempty"`
+	Status    SeatStatus `json:"status"`
+}
+
+// RowLabel names grid rows A to Z, then AA, AB and so on.
+func RowLabel(row int) string {
+	label := ""
+	for row > 0 {
+		row--
+		label = string(rune('A'+row%26)) + label
+		row /= 26
+	}
+	return label
+}
+
+// NewSeatGrid returns a rows x columns grid where every cell is a gap.
+func NewSeatGrid(rows, columns int) []SeatLayoutRow {
+	grid := make([]SeatLayoutRow, rows)
+	for r := range grid {
+		grid[r] = SeatLayoutRow{Row: r + 1, Label: RowLabel(r + 1), Cells: make([]SeatLayoutCell, columns)}
+		for c := range grid[r].Cells {
+			grid[r].Cells[c] = SeatLayoutCell{Column: c + 1, Status: SeatGap}
+		}
+	}
+	return grid
+}
+
+// Layout places the seats of the auditorium on its grid, all of them available.
+func (a *Auditorium) Layout() *SeatLayout {
+	layout := SeatLayout{AuditoriumID: a.ID, Auditorium: a.Name, Rows: a.Rows, Columns: a.Columns, Grid: NewSeatGrid(a.Rows, a.Columns)}
+	for _, s := range a.Seats {
+		if s.Row < 1 || s.Row > a.Rows || s.Column < 1 || s.Column > a.Columns {
+			continue
+		}
+		layout.Grid[s.Row-1].Cells[s.Column-1] = SeatLayoutCell{Column: s.Column, SeatCode: s.SeatCode, SeatClass: s.SeatClass, Status: SeatAvailable}
+	}
+	return &layout
+}
+
+// Find returns the grid position of a seat code as zero based row and column indexes.
+func (l *SeatLayout) Find(seatCode string) (int, int, bool) {
+	for r, row := range l.Grid {
+		for c, cell := range row.Cells {
+			if cell.Status != SeatGap && cell.SeatCode == seatCode {
+				return r, c, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// CouplePartner returns the column index of the seat sold together with the couple seat
+// at r, c. Neighbouring couple seats of a row pair up from the left, so a run of them
+// must have an even length.
+func (l *SeatLayout) CouplePartner(r, c int) (int, bool) {
+	cells := l.Grid[r].Cells
+	if cells[c].SeatClass != SeatClassCouple {
+		return 0, false
+	}
+	start := c
+	for start > 0 && cells[start-1].Status != SeatGap && cells[start-1].SeatClass == SeatClassCouple {
+		start--
+	}
+	partner := c + 1
+	if (c-start)%2 == 1 {
+		partner = c - 1
+	}
+	if partner < 0 || partner >= len(cells) || cells[partner].Status == SeatGap || cells[partner].SeatClass != SeatClassCouple {
+		return 0, false
+	}
+	return partner, true
+}
+
+// NextToWheelchair reports whether the seat at r, c sits beside a wheelchair space.
+func (l *SeatLayout) NextToWheelchair(r, c int) bool {
+	cells := l.Grid[r].Cells
+	for _, n := range []int{c - 1, c + 1} {
+		if n >= 0 && n < len(cells) && cells[n].Status != SeatGap && cells[n].SeatClass == SeatClassWheelchair {
+			return true
+		}
+	}
+	return false
+}
+
+// Problem describes the first seat placement that breaks the seat class rules: couple
+// seats without a partner and companion seats away from a wheelchair space.
+func (l *SeatLayout) Problem() string {
+	for r, row := range l.Grid {
+		for c, cell := range row.Cells {
+			if cell.Status == SeatGap {
+				continue
+			}
+			switch cell.SeatClass {
+			case SeatClassCouple:
+				if _, ok := l.CouplePartner(r, c); !ok {
+					return "Couple seat " + cell.SeatCode + " has no partner seat beside it"
+				}
+			case SeatClassCompanion:
+				if !l.NextToWheelchair(r, c) {
+					return "Companion seat " + cell.SeatCode + " must be beside a wheelchair space"
+				}
+			}
+		}
+	}
+	return ""
+}
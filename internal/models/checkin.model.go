@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type Admission struct {
+	OrderID     int         `json:"order_id"`
+	ScheduleID  int         `json:"schedule_id"`
+	Status      OrderStatus `json:"status"`
+	SeatCodes   []string    `json:"seat_codes"`
+	Remaining   []string    `json:"remaining_seat_codes"`
+	AdmittedAt  time.Time   `json:"admitted_at"`
+	ShowStartAt time.Time   `json:"show_start_at"`
+}
+
+type AdmissionSummary struct {
+	ScheduleID      int        `json:"schedule_id"`
+	CinemaID        int        `json:"cinema_id"`
+	ShowStartAt     time.Time  `json:"show_start_at"`
+	OrdersSold      int        `json:"orders_sold"`
+	OrdersCheckedIn int        `json:"orders_checked_in"`
+	SeatsSold       int        `json:"seats_sold"`
+	SeatsAdmitted   int        `json:"seats_admitted"`
+	LastAdmittedAt  *time.Time `json:"last_admitted_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+type EmailKind string
+
+const (
+	EmailTicket EmailKind = "ticket"
+)
+
+type EmailStatus string
+
+const (
+	EmailPending   EmailStatus = "pending"
+	EmailSending   EmailStatus = "sending"
+	EmailSent      EmailStatus = "sent"
+	EmailFailed    EmailStatus = "failed"
+	EmailCancelled EmailStatus = "cancelled"
+)
+
+// EmailDelivery is one email queued for an order, kept as its delivery log.
+type EmailDelivery struct {
+	ID            int         `db:"id" json:"id"`
+	OrderID       int         `db:"orders_id" json:"order_id"`
+	Reference     string      `db:"reference" json:"reference"`
+	Kind          EmailKind   `db:"kind" json:"kind"`
+	Recipient     string      `db:"recipient" json:"recipient"`
+	Subject       *string     `db:"subject" json:"subject"`
+	Status        EmailStatus `db:"status" json:"status"`
+	Attempts      int         `db:"attempts" json:"attempts"`
+	LastError     *string     `db:"last_error" json:"last_error"`
+	NextAttemptAt time.Time   `db:"next_attempt_at" json:"next_attempt_at"`
+	SentAt        *time.Time  `db:"sent_at" json:"sent_at"`
+	CreatedAt     time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt     *time.Time  `db:"updated_at" json:"updated_at"`
+}
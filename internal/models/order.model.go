@@ -1,32 +1,89 @@
 package models
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusCheckedIn OrderStatus = "checked_in"
+	OrderStatusExpired   OrderStatus = "expired"
+	OrderStatusRefunded  OrderStatus = "refunded"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
+
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending: {OrderStatusPaid, OrderStatusExpired, OrderStatusCancelled},
+	OrderStatusPaid:    {OrderStatusCheckedIn, OrderStatusRefunded, OrderStatusCancelled},
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	return slices.Contains(orderStatusTransitions[s], next)
+}
+
+// ReleasesSeats reports whether seats of an order in this status can be sold again.
+func (s OrderStatus) ReleasesSeats() bool {
+	return s == OrderStatusExpired || s == OrderStatusRefunded || s == OrderStatusCancelled
+}
+
 type Order struct {
-	ID         int        `db:"id" json:"id"`
-	QRCode     string     `db:"qr_code" json:"qr_code"`
-	UserID     uuid.UUID  `db:"users_id" json:"user_id"`
-	ScheduleID int        `db:"schedules_id" json:"schedule_id"`
-	PaymentID  int        `db:"payments_id" json:"payment_id"`
-	FullName   string     `db:"fullname" json:"fullname"`
-	Email      string     `db:"email" json:"email"`
-	Phone      string     `db:"phone_number" json:"phone"`
-	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
-	UpdatedAt  *time.Time `db:"updated_at" json:"updated_at"`
-	Seats      []Seat     `db:"-" json:"seats"`
+	ID         int         `db:"id" json:"id"`
+	Reference  string      `db:"reference" json:"reference"`
+	QRCode     string      `db:"qr_code" json:"qr_code,omitempty"`
+	UserID     uuid.UUID   `db:"users_id" json:"user_id"`
+	ScheduleID int         `db:"schedules_id" json:"schedule_id"`
+	PaymentID  int         `db:"payments_id" json:"payment_id"`
+	FullName   string      `db:"fullname" json:"fullname"`
+	Email      string      `db:"email" json:"email"`
+	Phone      string      `db:"phone_number" json:"phone"`
+	Status     OrderStatus `db:"status" json:"status"`
+	Subtotal   int64       `db:"subtotal" json:"subtotal"`
+	Fees       int64       `db:"fees" json:"fees"`
+	Discount   int64       `db:"discount" json:"discount"`
+	Total      int64       `db:"total" json:"total"`
+	CreatedAt  time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt  *time.Time  `db:"updated_at" json:"updated_at"`
+	Seats      []Seat      `db:"-" json:"seats"`
+
+	PointsRedeemed int    `db:"points_redeemed" json:"points_redeemed"`
+	PointsEarned   int    `db:"points_earned" json:"points_earned"`
+	PromoCode      string `db:"-" json:"promo_code,omitempty"`
+}
+
+// ActiveSeats leaves out the seats that were cancelled from the order.
+func (o Order) ActiveSeats() []Seat {
+	seats := make([]Seat, 0, len(o.Seats))
+	for _, s := range o.Seats {
+		if !s.Released {
+			seats = append(seats, s)
+		}
+	}
+	return seats
+}
+
+type OrderStatusHistory struct {
+	ID         int          `db:"id" json:"id"`
+	OrderID    int          `db:"orders_id" json:"order_id"`
+	FromStatus *OrderStatus `db:"from_status" json:"from_status"`
+	ToStatus   OrderStatus  `db:"to_status" json:"to_status"`
+	Note       *string      `db:"note" json:"note"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
 }
 
 type Schedule struct {
-	ID         int       `db:"id" json:"id"`
-	MovieID    int       `db:"movies_id" json:"movie_id"`
-	CinemaID   int       `db:"cinemas_id" json:"cinema_id"`
-	TimeID     int       `db:"times_id" json:"time_id"`
-	LocationID int       `db:"locations_id" json:"location_id"`
-	Date       time.Time `db:"date" json:"date"`
+	ID           int       `db:"id" json:"id"`
+	MovieID      int       `db:"movies_id" json:"movie_id"`
+	CinemaID     int       `db:"cinemas_id" json:"cinema_id"`
+	TimeID       int       `db:"times_id" json:"time_id"`
+	LocationID   int       `db:"locations_id" json:"location_id"`
+	AuditoriumID int       `db:"auditoriums_id" json:"auditorium_id"`
+	Date         time.Time `db:"date" json:"date"`
 }
 
 // komposite pk
@@ -36,13 +93,45 @@ type OrderSeat struct {
 }
 
 type Seat struct {
-	ID       int    `db:"id" json:"id"`
-	SeatCode string `db:"seat_code" json:"seat_code"`
+	ID        int    `db:"id" json:"id"`
+	SeatCode  string `db:"seat_code" json:"seat_code"`
+	SeatClass string `db:"seat_class" json:"seat_class,omitempty"`
+	Price     int64  `db:"price" json:"price,omitempty"`
+	Released  bool   `db:"released" json:"released,omitempty"`
+}
+
+type PriceLine struct {
+	SeatID        int    `json:"seat_id"`
+	SeatCode      string `json:"seat_code"`
+	SeatClass     string `json:"seat_class"`
+	BasePrice     int64  `json:"base_price"`
+	DaySurcharge  int64  `json:"day_surcharge"`
+	TimeSurcharge int64  `json:"time_surcharge"`
+	SeatSurcharge int64  `json:"seat_surcharge"`
+	Price         int64  `json:"price"`
+}
+
+type PriceQuote struct {
+	ScheduleID int         `json:"schedule_id"`
+	DayType    string      `json:"day_type"`
+	Lines      []PriceLine `json:"lines"`
+	Subtotal   int64       `json:"subtotal"`
+	Fees       int64       `json:"fees"`
+	Discount   int64       `json:"discount"`
+	Total      int64       `json:"total"`
+	PromoCode  string      `json:"promo_code,omitempty"`
+}
+
+type SeatHold struct {
+	ScheduleID int       `json:"schedule_id"`
+	SeatCodes  []string  `json:"seat_codes"`
+	ExpiresAt  time.Time `json:"expires_at"`
 }
 
 type PaymentMethod struct {
-	ID   int    `db:"id" json:"id"`
-	Name string `db:"name" json:"name"`
+	ID       int    `db:"id" json:"id"`
+	Name     string `db:"name" json:"name"`
+	Provider string `db:"provider" json:"provider"`
 }
 
 type Cinema struct {
@@ -62,10 +151,21 @@ type Time struct {
 
 type OrderDetail struct {
 	Order
-	Movie       Movie     `json:"movie"`
-	CinemaName  string    `json:"cinema_name"`
-	Location    string    `json:"location"`
-	TimeStr     string    `json:"time"`
-	Date        time.Time `json:"date"`
-	PaymentName string    `json:"payment"`
+	Movie         Movie                `json:"movie"`
+	CinemaName    string               `json:"cinema_name"`
+	Location      string               `json:"location"`
+	TimeStr       string               `json:"time"`
+	Date          time.Time            `json:"date"`
+	PaymentName   string               `json:"payment"`
+	StatusHistory []OrderStatusHistory `json:"status_history"`
+}
+
+// ExpiredOrder is a pending order whose payment window elapsed and whose seats went back
+// on sale.
+type ExpiredOrder struct {
+	OrderID    int       `json:"order_id"`
+	Reference  string    `json:"reference"`
+	UserID     uuid.UUID `json:"user_id"`
+	ScheduleID int       `json:"schedule_id"`
+	SeatCodes  []string  `json:"seat_codes"`
 }
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentTransaction struct {
+	ID          int        `db:"id" json:"id"`
+	OrderID     int        `db:"orders_id" json:"order_id"`
+	Provider    string     `db:"provider" json:"provider"`
+	Reference   string     `db:"reference" json:"reference"`
+	Amount      int64      `db:"amount" json:"amount"`
+	Status      string     `db:"status" json:"status"`
+	RedirectURL *string    `db:"redirect_url" json:"redirect_url"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   *time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type RefundStatus string
+
+const (
+	RefundPending  RefundStatus = "pending"
+	RefundRefunded RefundStatus = "refunded"
+	RefundFailed   RefundStatus = "failed"
+	// RefundManual marks refunds of orders paid outside a payment provider.
+	RefundManual RefundStatus = "manual"
+)
+
+type OrderRefund struct {
+	ID              int          `db:"id" json:"id"`
+	OrderID         int          `db:"orders_id" json:"order_id"`
+	Provider        *string      `db:"provider" json:"provider"`
+	ChargeReference *string      `db:"charge_reference" json:"-"`
+	Reference       *string      `db:"reference" json:"reference"`
+	Amount          int64        `db:"amount" json:"amount"`
+	SeatCodes       []string     `db:"seat_codes" json:"seat_codes"`
+	Reason          *string      `db:"reason" json:"reason"`
+	Status          RefundStatus `db:"status" json:"status"`
+	RequestedBy     uuid.UUID    `db:"requested_by" json:"requested_by"`
+	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt       *time.Time   `db:"updated_at" json:"updated_at"`
+}
+
+type Cancellation struct {
+	OrderID   int          `json:"order_id"`
+	Status    OrderStatus  `json:"status"`
+	SeatCodes []string     `json:"seat_codes"`
+	Refund    *OrderRefund `json:"refund"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PointKind string
+
+const (
+	PointEarn    PointKind = "earn"
+	PointRedeem  PointKind = "redeem"
+	PointReverse PointKind = "reverse"
+	PointRestore PointKind = "restore"
+	PointAdjust  PointKind = "adjust"
+)
+
+// PointEntry is one line of a user's points ledger. Points is signed and Balance is the
+// user's balance right after the entry.
+type PointEntry struct {
+	ID        int       `db:"id" json:"id"`
+	UserID    uuid.UUID `db:"users_id" json:"-"`
+	OrderID   *int      `db:"orders_id" json:"order_id"`
+	Reference *string   `db:"reference" json:"reference,omitempty"`
+	Kind      PointKind `db:"kind" json:"kind"`
+	Points    int       `db:"points" json:"points"`
+	Balance   int       `db:"balance" json:"balance"`
+	Note      *string   `db:"note" json:"note,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type PointHistory struct {
+	Balance int          `json:"balance"`
+	Entries []PointEntry `json:"entries"`
+}
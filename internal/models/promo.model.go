@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+type PromoType string
+
+const (
+	PromoPercent PromoType = "percent"
+	PromoFixed   PromoType = "fixed"
+)
+
+// PromoCode is a discount on the tickets of an order. Empty id lists do not restrict,
+// nil limits are unlimited.
+type PromoCode struct {
+	ID            int        `db:"id" json:"id"`
+	Code          string     `db:"code" json:"code"`
+	Description   *string    `db:"description" json:"description"`
+	DiscountType  PromoType  `db:"discount_type" json:"discount_type"`
+	DiscountValue int64      `db:"discount_value" json:"discount_value"`
+	MaxDiscount   *int64     `db:"max_discount" json:"max_discount"`
+	MinTickets    int        `db:"min_tickets" json:"min_tickets"`
+	StartsAt      *time.Time `db:"starts_at" json:"starts_at"`
+	EndsAt        *time.Time `db:"ends_at" json:"ends_at"`
+	MovieIDs      []int      `db:"movie_ids" json:"movie_ids"`
+	CinemaIDs     []int      `db:"cinema_ids" json:"cinema_ids"`
+	PaymentIDs    []int      `db:"payment_ids" json:"payment_ids"`
+	UsageLimit    *int       `db:"usage_limit" json:"usage_limit"`
+	PerUserLimit  *int       `db:"per_user_limit" json:"per_user_limit"`
+	UsedCount     int        `db:"used_count" json:"used_count"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt     *time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// DiscountFor is the discount the promo gives on a ticket subtotal.
+func (p *PromoCode) DiscountFor(subtotal int64) int64 {
+	discount := p.DiscountValue
+	if p.DiscountType == PromoPercent {
+		discount = subtotal * p.DiscountValue / 100
+		if p.MaxDiscount != nil {
+			discount = min(discount, *p.MaxDiscount)
+		}
+	}
+	return max(min(discount, subtotal), 0)
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Notification channels a reminder can go out on.
+const (
+	ChannelEmail = "email"
+	ChannelPush  = "push"
+	ChannelSMS   = "sms"
+)
+
+type ReminderStatus string
+
+const (
+	ReminderPending   ReminderStatus = "pending"
+	ReminderSending   ReminderStatus = "sending"
+	ReminderSent      ReminderStatus = "sent"
+	ReminderFailed    ReminderStatus = "failed"
+	ReminderCancelled ReminderStatus = "cancelled"
+)
+
+// Reminder is a showtime reminder scheduled for an order on one notification channel.
+type Reminder struct {
+	ID        int            `db:"id" json:"id"`
+	OrderID   int            `db:"orders_id" json:"order_id"`
+	Reference string         `db:"reference" json:"reference"`
+	Channel   string         `db:"channel" json:"channel"`
+	Status    ReminderStatus `db:"status" json:"status"`
+	Attempts  int            `db:"attempts" json:"attempts"`
+	LastError *string        `db:"last_error" json:"last_error"`
+	SendAt    time.Time      `db:"send_at" json:"send_at"`
+	SentAt    *time.Time     `db:"sent_at" json:"sent_at"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt *time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+// NotificationPreferences are the channels a user wants showtime reminders on and how
+// many hours before the show they go out.
+type NotificationPreferences struct {
+	Email         bool `db:"notify_email" json:"email"`
+	Push          bool `db:"notify_push" json:"push"`
+	SMS           bool `db:"notify_sms" json:"sms"`
+	ReminderHours int  `db:"reminder_hours" json:"reminder_hours"`
+}
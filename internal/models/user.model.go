@@ -10,19 +10,30 @@ type Role string
 
 const (
 	RoleAdmin Role = "admin"
+	RoleStaff Role = "staff"
 	RoleUser  Role = "user"
 )
 
 type User struct {
-	ID        uuid.UUID  `db:"id"`
-	Email     string     `db:"email"`
-	Password  string     `db:"password"`
-	Role      Role       `db:"role"`
-	CreatedAt time.Time  `db:"created_at"`
-	UpdatedAt *time.Time `db:"updated_at"`
-	Profile   Profile    `db:"-"`
+	ID         uuid.UUID  `db:"id"`
+	Email      string     `db:"email"`
+	Password   string     `db:"password"`
+	Role       Role       `db:"role"`
+	VerifiedAt *time.Time `db:"verified_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  *time.Time `db:"updated_at"`
+	Profile    Profile    `db:"-"`
 }
 
+// UserTokenPurpose is what a token mailed to a user can be used for.
+type UserTokenPurpose string
+
+const (
+	UserTokenVerifyEmail   UserTokenPurpose = "verify_email"
+	UserTokenResetPassword UserTokenPurpose = "reset_password"
+	UserTokenUnlockAccount UserTokenPurpose = "unlock_account"
+)
+
 type Profile struct {
 	UserID      uuid.UUID  `db:"user_id" json:"user_id"`
 	FirstName   *string    `db:"firstname" json:"firstname,omitempty"`
@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"net/mail"
+
+	"github.com/Darari17/be-tickitz-full/internal/mailer"
+	"github.com/Darari17/be-tickitz-full/internal/models"
+)
+
+var notificationHTML = template.Must(template.New("notification.html").Parse(`<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f5f6f8;font-family:Arial,Helvetica,sans-serif;color:#14142b">
+<div style="max-width:560px;margin:0 auto;background:#ffffff;border-radius:8px;padding:24px">
+  <h2 style="margin:0 0 12px">{{.Title}}</h2>
+  <p style="margin:0 0 16px">{{.Body}}</p>
+  {{if .URL}}<p style="margin:0"><a href="{{.URL}}" style="color:#5f2eea">View your ticket</a></p>{{end}}
+</div>
+</body>
+</html>
+`))
+
+// EmailChannel sends notifications through the mail transport.
+type EmailChannel struct {
+	transport mailer.Transport
+}
+
+func NewEmailChannel(transport mailer.Transport) *EmailChannel {
+	return &EmailChannel{transport: transport}
+}
+
+func (e *EmailChannel) Name() string {
+	return models.ChannelEmail
+}
+
+func (e *EmailChannel) Send(ctx context.Context, n Notification) error {
+	if n.To.Email == "" {
+		return ErrNoAddress
+	}
+
+	var html bytes.Buffer
+	if err := notificationHTML.Execute(&html, n); err != nil {
+		return err
+	}
+
+	text := n.Body
+	if n.URL != "" {
+		text += "\n\n" + n.URL
+	}
+
+	return e.transport.Send(ctx, &mailer.Message{
+		To:      (&mail.Address{Name: n.To.Name, Address: n.To.Email}).String(),
+		Subject: n.Title,
+		Text:    text + "\n\nTickitz\n",
+		HTML:    html.String(),
+	})
+}
@@ -0,0 +1,35 @@
+package notify
+
+import (
+	"context"
+	"log"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+)
+
+// FakeChannel stands in for a push or SMS gateway during development: it logs every
+// notification instead of delivering it.
+type FakeChannel struct {
+	name string
+}
+
+func NewFakeChannel(name string) *FakeChannel {
+	return &FakeChannel{name: name}
+}
+
+func (f *FakeChannel) Name() string {
+	return f.name
+}
+
+func (f *FakeChannel) Send(ctx context.Context, n Notification) error {
+	to := n.To.Email
+	if f.name == models.ChannelSMS {
+		if n.To.Phone == "" {
+			return ErrNoAddress
+		}
+		to = n.To.Phone
+	}
+
+	log.Printf("[%s] to %s: %s - %s\n", f.name, to, n.Title, n.Body)
+	return nil
+}
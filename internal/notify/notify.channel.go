@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+)
+
+// ErrNoAddress is returned by a channel when the recipient has no address on it, such
+// as an SMS to a user without a phone number. Retrying does not help.
+var ErrNoAddress = errors.New("recipient has no address on this channel")
+
+type Recipient struct {
+	Name  string
+	Email string
+	Phone string
+}
+
+type Notification struct {
+	To    Recipient
+	Title string
+	Body  string
+	URL   string
+}
+
+// Channel delivers notifications to users. Reminders select their channel by name,
+// which matches the preference flags stored on profile.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, n Notification) error
+}
+
+type Registry struct {
+	channels map[string]Channel
+}
+
+func NewRegistry(channels ...Channel) *Registry {
+	r := &Registry{channels: map[string]Channel{}}
+	for _, c := range channels {
+		r.channels[c.Name()] = c
+	}
+	return r
+}
+
+func (r *Registry) Get(name string) (Channel, bool) {
+	c, ok := r.channels[name]
+	return c, ok
+}
+
+// ShowtimeReminder tells the owner of a paid order that their show is coming up.
+func ShowtimeReminder(order *models.OrderDetail) Notification {
+	seats := make([]string, 0, len(order.Seats))
+	for _, s := range order.ActiveSeats() {
+		seats = append(seats, s.SeatCode)
+	}
+
+	return Notification{
+		To:    Recipient{Name: order.FullName, Email: order.Email, Phone: order.Phone},
+		Title: fmt.Sprintf("%s starts at %s", order.Movie.Title, utils.FormatShowTime(order.TimeStr)),
+		Body: fmt.Sprintf("Your show %s starts %s at %s in %s, %s. Seats %s, booking reference %s.",
+			order.Movie.Title, order.Date.Format("Monday, 2 January"), utils.FormatShowTime(order.TimeStr),
+			order.CinemaName, order.Location, strings.Join(seats, ", "), order.Reference),
+		URL: utils.GetBaseURL() + "/orders/" + order.Reference,
+	}
+}
@@ -0,0 +1,163 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const (
+	FakeProviderName    = "fake"
+	FakeSignatureHeader = "X-Fake-Signature"
+)
+
+// FakeProvider is an in-process gateway for development and tests. Charges live in
+// memory and are settled through Complete instead of a real checkout page.
+type FakeProvider struct {
+	secret  []byte
+	baseURL string
+
+	mu       sync.Mutex
+	charges  map[string]*Charge
+	refunded map[string]int64
+}
+
+var ErrFakeSecretMissing = errors.New("FAKE_PAYMENT_SECRET is not set")
+
+// NewFakeProvider refuses to run without FAKE_PAYMENT_SECRET, since its webhooks are
+// only as trustworthy as that secret.
+func NewFakeProvider() (*FakeProvider, error) {
+	secret := os.Getenv("FAKE_PAYMENT_SECRET")
+	if secret == "" {
+		return nil, ErrFakeSecretMissing
+	}
+
+	baseURL := os.Getenv("APP_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+
+	return &FakeProvider{
+		secret:   []byte(secret),
+		baseURL:  baseURL,
+		charges:  map[string]*Charge{},
+		refunded: map[string]int64{},
+	}, nil
+}
+
+func (f *FakeProvider) Name() string {
+	return FakeProviderName
+}
+
+func (f *FakeProvider) CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error) {
+	reference := "fake_" + uuid.NewString()
+	charge := &Charge{
+		Provider:    FakeProviderName,
+		Reference:   reference,
+		Amount:      req.Amount,
+		Status:      ChargePending,
+		RedirectURL: fmt.Sprintf("%s/payments/fake/%s/pay", f.baseURL, reference),
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.charges[reference] = charge
+
+	copied := *charge
+	return &copied, nil
+}
+
+func (f *FakeProvider) QueryStatus(ctx context.Context, reference string) (ChargeStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	charge, ok := f.charges[reference]
+	if !ok {
+		return "", ErrChargeNotFound
+	}
+	return charge.Status, nil
+}
+
+func (f *FakeProvider) Refund(ctx context.Context, reference string, amount int64) (*Refund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	charge, ok := f.charges[reference]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+	if charge.Status != ChargePaid || f.refunded[reference]+amount > charge.Amount {
+		return nil, ErrNotRefundable
+	}
+
+	f.refunded[reference] += amount
+	if f.refunded[reference] == charge.Amount {
+		charge.Status = ChargeRefunded
+	}
+
+	return &Refund{
+		Reference: "fake_refund_" + uuid.NewString(),
+		Amount:    amount,
+		Status:    ChargeRefunded,
+	}, nil
+}
+
+// Complete settles a pending charge and returns the signed webhook the gateway would send.
+func (f *FakeProvider) Complete(reference string, status ChargeStatus) (http.Header, []byte, error) {
+	f.mu.Lock()
+	charge, ok := f.charges[reference]
+	var amount int64
+	if ok {
+		if charge.Status == ChargePending {
+			charge.Status = status
+		}
+		amount = charge.Amount
+	}
+	f.mu.Unlock()
+
+	if !ok {
+		return nil, nil, ErrChargeNotFound
+	}
+
+	body, err := json.Marshal(WebhookEvent{Reference: reference, Status: status, Amount: amount})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := http.Header{}
+	header.Set(FakeSignatureHeader, f.sign(body))
+	return header, body, nil
+}
+
+func (f *FakeProvider) VerifyWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	signature, err := hex.DecodeString(header.Get(FakeSignatureHeader))
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	expected, _ := hex.DecodeString(f.sign(body))
+	if !hmac.Equal(signature, expected) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (f *FakeProvider) sign(body []byte) string {
+	mac := hmac.New(sha256.New, f.secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,87 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+var (
+	ErrChargeNotFound   = errors.New("charge not found")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrNotRefundable    = errors.New("charge cannot be refunded")
+)
+
+type ChargeStatus string
+
+const (
+	ChargePending  ChargeStatus = "pending"
+	ChargePaid     ChargeStatus = "paid"
+	ChargeFailed   ChargeStatus = "failed"
+	ChargeRefunded ChargeStatus = "refunded"
+)
+
+type ChargeRequest struct {
+	OrderID     int
+	Amount      int64
+	Email       string
+	Description string
+}
+
+type Charge struct {
+	Provider    string       `json:"provider"`
+	Reference   string       `json:"reference"`
+	Amount      int64        `json:"amount"`
+	Status      ChargeStatus `json:"status"`
+	RedirectURL string       `json:"redirect_url"`
+}
+
+type Refund struct {
+	Reference string       `json:"reference"`
+	Amount    int64        `json:"amount"`
+	Status    ChargeStatus `json:"status"`
+}
+
+// WebhookEvent is a charge status change reported by a provider. Amount is what the
+// customer actually paid.
+type WebhookEvent struct {
+	Reference string       `json:"reference"`
+	Status    ChargeStatus `json:"status"`
+	Amount    int64        `json:"amount"`
+}
+
+// Provider is implemented by every payment gateway. Payment methods select their
+// provider through the payment_methods.provider column.
+type Provider interface {
+	Name() string
+	CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error)
+	QueryStatus(ctx context.Context, reference string) (ChargeStatus, error)
+	Refund(ctx context.Context, reference string, amount int64) (*Refund, error)
+	VerifyWebhook(header http.Header, body []byte) (*WebhookEvent, error)
+}
+
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: map[string]Provider{}}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+// Names lists the registered providers.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (r *Registry) Get(name string) (Provider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
@@ -2,14 +2,23 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var (
+	ErrUserNotFound   = errors.New("user not found")
+	ErrCinemaNotFound = errors.New("cinema not found")
+)
+
 type AdminRepo struct {
 	db *pgxpool.Pool
 }
@@ -66,11 +75,16 @@ func (r *AdminRepo) CreateMovie(
 		locationID := s["location_id"].(int)
 		timeIDs := s["time_ids"].([]int)
 
+		auditoriumID, err := scheduleAuditorium(ctx, tx, s["auditorium_id"].(int), cinemaID, locationID)
+		if err != nil {
+			return nil, err
+		}
+
 		for _, tid := range timeIDs {
 			_, err := tx.Exec(ctx, `
-				INSERT INTO schedules (movies_id, cinemas_id, locations_id, times_id, date)
-				VALUES ($1,$2,$3,$4,$5)
-			`, movie.ID, cinemaID, locationID, tid, date)
+				INSERT INTO schedules (movies_id, cinemas_id, locations_id, auditoriums_id, times_id, date)
+				VALUES ($1,$2,$3,$4,$5,$6)
+			`, movie.ID, cinemaID, locationID, auditoriumID, tid, date)
 			if err != nil {
 				return nil, err
 			}
@@ -87,6 +101,32 @@ func NewAdminRepo(db *pgxpool.Pool) *AdminRepo {
 	return &AdminRepo{db: db}
 }
 
+// scheduleAuditorium checks that the auditorium belongs to the schedule's cinema and
+// location. Without one, the first hall there is used, then the legacy shared hall.
+func scheduleAuditorium(ctx context.Context, tx pgx.Tx, auditoriumID, cinemaID, locationID int) (int, error) {
+	if auditoriumID != 0 {
+		var exists bool
+		err := tx.QueryRow(ctx, `
+			SELECT EXISTS(SELECT 1 FROM auditoriums WHERE id=$1 AND cinemas_id=$2 AND locations_id=$3 AND deleted_at IS NULL)
+		`, auditoriumID, cinemaID, locationID).Scan(&exists)
+		if err != nil || !exists {
+			return 0, fmt.Errorf("auditorium id %d not found at cinema %d, location %d", auditoriumID, cinemaID, locationID)
+		}
+		return auditoriumID, nil
+	}
+
+	err := tx.QueryRow(ctx, `
+		SELECT id FROM auditoriums
+		WHERE deleted_at IS NULL AND ((cinemas_id=$1 AND locations_id=$2) OR cinemas_id IS NULL)
+		ORDER BY cinemas_id NULLS LAST, id
+		LIMIT 1
+	`, cinemaID, locationID).Scan(&auditoriumID)
+	if err != nil {
+		return 0, fmt.Errorf("no auditorium at cinema %d, location %d", cinemaID, locationID)
+	}
+	return auditoriumID, nil
+}
+
 func (r *AdminRepo) GetMovies(ctx context.Context) ([]models.Movie, error) {
 	rows, err := r.db.Query(ctx, `
 		SELECT id, backdrop_path, overview, popularity, poster_path,
@@ -232,3 +272,22 @@ func (r *AdminRepo) UpdateMovie(
 
 	return tx.Commit(ctx)
 }
+
+// AssignStaff gives a user the staff role at one cinema.
+func (r *AdminRepo) AssignStaff(ctx context.Context, userID uuid.UUID, cinemaID int) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE users SET role = $2, cinemas_id = $3, updated_at = NOW()
+		WHERE id = $1 AND role <> $4
+	`, userID, models.RoleStaff, cinemaID, models.RoleAdmin)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrCinemaNotFound
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrAuditoriumNotFound = errors.New("auditorium not found")
+	ErrAuditoriumInUse    = errors.New("auditorium is in use")
+)
+
+// LayoutError reports a seat map that cannot be saved, e.g. a seat outside the grid.
+type LayoutError struct {
+	Message string
+}
+
+func (e *LayoutError) Error() string {
+	return e.Message
+}
+
+type AuditoriumRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditoriumRepo(db *pgxpool.Pool) *AuditoriumRepo {
+	return &AuditoriumRepo{db: db}
+}
+
+func (ar *AuditoriumRepo) GetAuditoriums(ctx context.Context, cinemaID, locationID int) ([]models.Auditorium, error) {
+	rows, err := ar.db.Query(ctx, `
+		SELECT id, cinemas_id, locations_id, name, row_count, column_count, created_at, updated_at
+		FROM auditoriums
+		WHERE deleted_at IS NULL
+		  AND ($1 = 0 OR cinemas_id = $1)
+		  AND ($2 = 0 OR locations_id = $2)
+		ORDER BY cinemas_id NULLS FIRST, locations_id, name
+	`, cinemaID, locationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	auditoriums := []models.Auditorium{}
+	for rows.Next() {
+		var a models.Auditorium
+		if err := rows.Scan(&a.ID, &a.CinemaID, &a.LocationID, &a.Name, &a.Rows, &a.Columns, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		auditoriums = append(auditoriums, a)
+	}
+	return auditoriums, rows.Err()
+}
+
+// GetAuditoriumByID returns a hall together with its seats ordered by position.
+func (ar *AuditoriumRepo) GetAuditoriumByID(ctx context.Context, id int) (*models.Auditorium, error) {
+	var a models.Auditorium
+	err := ar.db.QueryRow(ctx, `
+		SELECT id, cinemas_id, locations_id, name, row_count, column_count, created_at, updated_at
+		FROM auditoriums
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id).Scan(&a.ID, &a.CinemaID, &a.LocationID, &a.Name, &a.Rows, &a.Columns, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAuditoriumNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := ar.db.Query(ctx, `
+		SELECT id, seat_code, seat_class, row_number, column_number
+		FROM seats
+		WHERE auditoriums_id = $1
+		ORDER BY row_number, column_number
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	a.Seats = []models.AuditoriumSeat{}
+	for rows.Next() {
+		var s models.AuditoriumSeat
+		if err := rows.Scan(&s.ID, &s.SeatCode, &s.SeatClass, &s.Row, &s.Column); err != nil {
+			return nil, err
+		}
+		a.Seats = append(a.Seats, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// CreateAuditorium stores a hall and its seats. Without seats the whole grid is filled
+// with regular seats.
+func (ar *AuditoriumRepo) CreateAuditorium(ctx context.Context, a models.Auditorium) (*models.Auditorium, error) {
+	if len(a.Seats) == 0 {
+		for r := 1; r <= a.Rows; r++ {
+			for c := 1; c <= a.Columns; c++ {
+				a.Seats = append(a.Seats, models.AuditoriumSeat{Row: r, Column: c})
+			}
+		}
+	}
+
+	tx, err := ar.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO auditoriums (cinemas_id, locations_id, name, row_count, column_count)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, a.CinemaID, a.LocationID, a.Name, a.Rows, a.Columns).Scan(&a.ID, &a.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, &LayoutError{Message: "Cinema or location not found"}
+		}
+		return nil, err
+	}
+
+	if err := insertSeats(ctx, tx, &a); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (ar *AuditoriumRepo) UpdateAuditorium(ctx context.Context, id int, name string) error {
+	tag, err := ar.db.Exec(ctx, `
+		UPDATE auditoriums SET name = $2, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id, name)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAuditoriumNotFound
+	}
+	return nil
+}
+
+// ReplaceLayout swaps the seat map of a hall. It is refused once any seat of the hall
+// has been sold, since order history points at those seats.
+func (ar *AuditoriumRepo) ReplaceLayout(ctx context.Context, id, rows, columns int, seats []models.AuditoriumSeat) (*models.Auditorium, error) {
+	tx, err := ar.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	a := models.Auditorium{ID: id, Rows: rows, Columns: columns, Seats: seats}
+	err = tx.QueryRow(ctx, `
+		UPDATE auditoriums SET row_count = $2, column_count = $3, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING cinemas_id, locations_id, name, created_at, updated_at
+	`, id, rows, columns).Scan(&a.CinemaID, &a.LocationID, &a.Name, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAuditoriumNotFound
+		}
+		return nil, err
+	}
+
+	var sold bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM order_seats os
+			JOIN seats se ON se.id = os.seats_id
+			WHERE se.auditoriums_id = $1
+		)
+	`, id).Scan(&sold)
+	if err != nil {
+		return nil, err
+	}
+	if sold {
+		return nil, ErrAuditoriumInUse
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM seats WHERE auditoriums_id = $1`, id); err != nil {
+		return nil, err
+	}
+	if err := insertSeats(ctx, tx, &a); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// DeleteAuditorium soft deletes a hall that has no upcoming schedules.
+func (ar *AuditoriumRepo) DeleteAuditorium(ctx context.Context, id int) error {
+	var upcoming bool
+	err := ar.db.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM schedules WHERE auditoriums_id = $1 AND date >= CURRENT_DATE)
+	`, id).Scan(&upcoming)
+	if err != nil {
+		return err
+	}
+	if upcoming {
+		return ErrAuditoriumInUse
+	}
+
+	tag, err := ar.db.Exec(ctx, `
+		UPDATE auditoriums SET deleted_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAuditoriumNotFound
+	}
+	return nil
+}
+
+// insertSeats validates the seat map of a and inserts it. Missing classes default to
+// regular and missing codes to the row label followed by the column.
+func insertSeats(ctx context.Context, tx pgx.Tx, a *models.Auditorium) error {
+	classes := map[string]bool{}
+	rows, err := tx.Query(ctx, `SELECT code FROM seat_classes`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			rows.Close()
+			return err
+		}
+		classes[code] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	positions := map[[2]int]bool{}
+	codes := map[string]bool{}
+	for i := range a.Seats {
+		s := &a.Seats[i]
+		if s.Row < 1 || s.Row > a.Rows || s.Column < 1 || s.Column > a.Columns {
+			return &LayoutError{Message: fmt.Sprintf("Seat at row %d, column %d is outside the %dx%d grid", s.Row, s.Column, a.Rows, a.Columns)}
+		}
+		if positions[[2]int{s.Row, s.Column}] {
+			return &LayoutError{Message: fmt.Sprintf("More than one seat at row %d, column %d", s.Row, s.Column)}
+		}
+		positions[[2]int{s.Row, s.Column}] = true
+
+		if s.SeatClass == "" {
+			s.SeatClass = "regular"
+		}
+		if !classes[s.SeatClass] {
+			return &LayoutError{Message: "Unknown seat class " + s.SeatClass}
+		}
+
+		s.SeatCode = strings.ToUpper(strings.TrimSpace(s.SeatCode))
+		if s.SeatCode == "" {
+			s.SeatCode = models.RowLabel(s.Row) + strconv.Itoa(s.Column)
+		}
+		if codes[s.SeatCode] {
+			return &LayoutError{Message: "Seat code " + s.SeatCode + " is used more than once"}
+		}
+		codes[s.SeatCode] = true
+	}
+
+	if problem := a.Layout().Problem(); problem != "" {
+		return &LayoutError{Message: problem}
+	}
+
+	for i := range a.Seats {
+		s := &a.Seats[i]
+		err := tx.QueryRow(ctx, `
+			INSERT INTO seats (auditoriums_id, seat_code, seat_class, row_number, column_number)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, a.ID, s.SeatCode, s.SeatClass, s.Row, s.Column).Scan(&s.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
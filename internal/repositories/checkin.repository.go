@@ -0,0 +1,239 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/Darari17/be-tickitz-full/pkg"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrStaffNotAssigned = errors.New("staff is not assigned to a cinema")
+
+// CheckinRejectedError explains why a ticket was refused at the door. Reason is a
+// stable machine readable code, Message is meant for the staff member scanning.
+type CheckinRejectedError struct {
+	Reason     string
+	Message    string
+	SeatCodes  []string
+	AdmittedAt *time.Time
+}
+
+func (e *CheckinRejectedError) Error() string {
+	return e.Reason + ": " + e.Message
+}
+
+type CheckinRepo struct {
+	db          *pgxpool.Pool
+	opensBefore time.Duration
+	closesAfter time.Duration
+}
+
+func NewCheckinRepo(db *pgxpool.Pool) *CheckinRepo {
+	return &CheckinRepo{
+		db:          db,
+		opensBefore: utils.GetEnvDuration("CHECKIN_OPENS_BEFORE", time.Hour),
+		closesAfter: utils.GetEnvDuration("CHECKIN_CLOSES_AFTER", time.Hour),
+	}
+}
+
+// GetStaffCinema returns the cinema a staff member works at.
+func (cr *CheckinRepo) GetStaffCinema(ctx context.Context, userID uuid.UUID) (int, error) {
+	var cinemaID *int
+	if err := cr.db.QueryRow(ctx, `SELECT cinemas_id FROM users WHERE id = $1`, userID).Scan(&cinemaID); err != nil {
+		return 0, err
+	}
+	if cinemaID == nil {
+		return 0, ErrStaffNotAssigned
+	}
+	return *cinemaID, nil
+}
+
+// Admit marks the seats of a verified ticket as admitted. When seatCodes is empty every
+// seat not yet admitted is let in. cinemaID restricts the scan to one cinema; nil allows any.
+// The order moves to checked_in once all of its seats are admitted.
+func (cr *CheckinRepo) Admit(ctx context.Context, token string, ticket *pkg.TicketClaims, seatCodes []string, cinemaID *int, staffID uuid.UUID) (*models.Admission, error) {
+	tx, err := cr.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		status       models.OrderStatus
+		storedToken  string
+		scheduleID   int
+		showCinemaID int
+		startsAt     time.Time
+		now          time.Time
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT o.status, o.qr_code, o.schedules_id, s.cinemas_id,
+		       s.date + t.time::time, LOCALTIMESTAMP
+		FROM orders o
+		JOIN schedules s ON s.id = o.schedules_id
+		JOIN times t ON t.id = s.times_id
+		WHERE o.id = $1
+		FOR UPDATE OF o
+	`, ticket.OrderID).Scan(&status, &storedToken, &scheduleID, &showCinemaID, &startsAt, &now)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	if storedToken != token || scheduleID != ticket.ScheduleID {
+		return nil, &CheckinRejectedError{Reason: "ticket_revoked", Message: "Ticket is no longer valid for this order"}
+	}
+	if status != models.OrderStatusPaid && status != models.OrderStatusCheckedIn {
+		return nil, &CheckinRejectedError{Reason: "order_" + string(status), Message: "Order is " + string(status)}
+	}
+	if cinemaID != nil && *cinemaID != showCinemaID {
+		return nil, &CheckinRejectedError{Reason: "wrong_cinema", Message: "Ticket is for another cinema"}
+	}
+	if now.Before(startsAt.Add(-cr.opensBefore)) {
+		return nil, &CheckinRejectedError{Reason: "too_early", Message: "Check-in opens at " + startsAt.Add(-cr.opensBefore).Format("2006-01-02 15:04")}
+	}
+	if now.After(startsAt.Add(cr.closesAfter)) {
+		return nil, &CheckinRejectedError{Reason: "too_late", Message: "Check-in closed at " + startsAt.Add(cr.closesAfter).Format("2006-01-02 15:04")}
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT se.id, se.seat_code, os.admitted_at
+		FROM order_seats os
+		JOIN seats se ON se.id = os.seats_id
+		WHERE os.orders_id = $1 AND os.released_at IS NULL
+		ORDER BY se.seat_code
+		FOR UPDATE OF os
+	`, ticket.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seatIDs := map[string]int{}
+	admitted := map[string]*time.Time{}
+	var pending []string
+	for rows.Next() {
+		var id int
+		var code string
+		var admittedAt *time.Time
+		if err := rows.Scan(&id, &code, &admittedAt); err != nil {
+			return nil, err
+		}
+		seatIDs[code] = id
+		admitted[code] = admittedAt
+		if admittedAt == nil {
+			pending = append(pending, code)
+		}
+	}
+	rows.Close()
+
+	if len(seatCodes) == 0 {
+		if len(pending) == 0 {
+			var last *time.Time
+			for _, at := range admitted {
+				if last == nil || at.After(*last) {
+					last = at
+				}
+			}
+			return nil, &CheckinRejectedError{Reason: "already_admitted", Message: "All seats on this ticket were already admitted", AdmittedAt: last}
+		}
+		seatCodes = pending
+	}
+
+	var unknown, replayed []string
+	var replayedAt *time.Time
+	for _, code := range seatCodes {
+		at, ok := admitted[code]
+		switch {
+		case !ok:
+			unknown = append(unknown, code)
+		case at != nil:
+			replayed = append(replayed, code)
+			replayedAt = at
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, &CheckinRejectedError{Reason: "seat_not_on_ticket", Message: "Seats are not part of this ticket", SeatCodes: unknown}
+	}
+	if len(replayed) > 0 {
+		return nil, &CheckinRejectedError{Reason: "already_admitted", Message: "Seats were already admitted", SeatCodes: replayed, AdmittedAt: replayedAt}
+	}
+
+	ids := make([]int, 0, len(seatCodes))
+	for _, code := range seatCodes {
+		ids = append(ids, seatIDs[code])
+	}
+
+	admission := models.Admission{
+		OrderID:     ticket.OrderID,
+		ScheduleID:  scheduleID,
+		Status:      status,
+		SeatCodes:   seatCodes,
+		Remaining:   []string{},
+		ShowStartAt: startsAt,
+	}
+	err = tx.QueryRow(ctx, `
+		UPDATE order_seats SET admitted_at = LOCALTIMESTAMP, admitted_by = $3
+		WHERE orders_id = $1 AND seats_id = ANY($2) AND admitted_at IS NULL
+		RETURNING admitted_at
+	`, ticket.OrderID, ids, staffID).Scan(&admission.AdmittedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, code := range pending {
+		if !slices.Contains(seatCodes, code) {
+			admission.Remaining = append(admission.Remaining, code)
+		}
+	}
+
+	if len(admission.Remaining) == 0 && status == models.OrderStatusPaid {
+		if err := transitionStatus(ctx, tx, ticket.OrderID, status, models.OrderStatusCheckedIn, "Admitted at the door"); err != nil {
+			return nil, err
+		}
+		admission.Status = models.OrderStatusCheckedIn
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &admission, nil
+}
+
+func (cr *CheckinRepo) GetAdmissionSummary(ctx context.Context, scheduleID int) (*models.AdmissionSummary, error) {
+	var s models.AdmissionSummary
+	err := cr.db.QueryRow(ctx, `
+		SELECT s.id, s.cinemas_id, s.date + t.time::time,
+		       COUNT(DISTINCT o.id),
+		       COUNT(DISTINCT o.id) FILTER (WHERE o.status = $2),
+		       COUNT(os.seats_id),
+		       COUNT(os.admitted_at),
+		       MAX(os.admitted_at)
+		FROM schedules s
+		JOIN times t ON t.id = s.times_id
+		LEFT JOIN orders o ON o.schedules_id = s.id AND o.status IN ($2, $3)
+		LEFT JOIN order_seats os ON os.orders_id = o.id AND os.released_at IS NULL
+		WHERE s.id = $1
+		GROUP BY s.id, t.time
+	`, scheduleID, models.OrderStatusCheckedIn, models.OrderStatusPaid).Scan(
+		&s.ScheduleID, &s.CinemaID, &s.ShowStartAt,
+		&s.OrdersSold, &s.OrdersCheckedIn,
+		&s.SeatsSold, &s.SeatsAdmitted, &s.LastAdmittedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// reserve an attempt on an account as member ARGV[3] unless the client has to wait first:
+// {ms, locked}. After ARGV[4] free failures in the window each one doubles the delay from
+// ARGV[5] up to ARGV[6]. Reserved attempts count as failures until they are released.
+var reserveAccountScript = redis.NewScript(`
+local lock = redis.call('PTTL', KEYS[2])
+if lock > 0 then
+	return {lock, 1}
+end
+local now, window = tonumber(ARGV[1]), tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local fails = redis.call('ZCARD', KEYS[1])
+local free = tonumber(ARGV[4])
+if fails >= free then
+	local last = tonumber(redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')[2])
+	local delay = math.min(tonumber(ARGV[5]) * 2 ^ (fails - free), tonumber(ARGV[6]))
+	local wait = math.ceil(last + delay - now)
+	if wait > 0 then
+		return {wait, 0}
+	end
+end
+redis.call('ZADD', KEYS[1], now, ARGV[3])
+redis.call('PEXPIRE', KEYS[1], window)
+return {0, 0}
+`)
+
+// reserve an attempt from an address as member ARGV[3], or return the wait in ms until
+// fewer than ARGV[4] failures are left in the window
+var reserveAddressScript = redis.NewScript(`
+local now, window, max = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[4])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local fails = redis.call('ZCARD', KEYS[1])
+if fails >= max then
+	local oldest = tonumber(redis.call('ZRANGE', KEYS[1], fails - max, fails - max, 'WITHSCORES')[2])
+	return math.max(1, oldest + window - now)
+end
+redis.call('ZADD', KEYS[1], now, ARGV[3])
+redis.call('PEXPIRE', KEYS[1], window)
+return 0
+`)
+
+// record the failure of attempt ARGV[3]; with ARGV[4] set, lock KEYS[2] for ARGV[5] ms once the window holds
+// that many. Returns 1 only for the failure that locked the account.
+var recordFailureScript = redis.NewScript(`
+local now, window = tonumber(ARGV[1]), tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+redis.call('ZADD', KEYS[1], now, ARGV[3])
+redis.call('PEXPIRE', KEYS[1], window)
+if ARGV[4] ~= '' and redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[4]) then
+	if redis.call('SET', KEYS[2], now, 'PX', ARGV[5], 'NX') then
+		return 1
+	end
+end
+return 0
+`)
+
+// LoginGuard counts failed logins per account and per client address over a sliding
+// window. Failures on an account slow its next attempts down and eventually lock it;
+// failures from one address block that address for a while.
+type LoginGuard struct {
+	rdb *redis.Client
+
+	window       time.Duration
+	freeAttempts int
+	baseDelay    time.Duration
+	maxDelay     time.Duration
+	lockAfter    int
+	lockFor      time.Duration
+	maxPerIP     int
+}
+
+func NewLoginGuard(rdb *redis.Client) *LoginGuard {
+	return &LoginGuard{
+		rdb:          rdb,
+		window:       utils.GetEnvDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		freeAttempts: utils.GetEnvInt("LOGIN_FREE_ATTEMPTS", 3),
+		baseDelay:    utils.GetEnvDuration("LOGIN_DELAY_BASE", time.Second),
+		maxDelay:     utils.GetEnvDuration("LOGIN_DELAY_MAX", 30*time.Second),
+		lockAfter:    utils.GetEnvInt("LOGIN_LOCKOUT_THRESHOLD", 10),
+		lockFor:      utils.GetEnvDuration("LOGIN_LOCKOUT_DURATION", 30*time.Minute),
+		maxPerIP:     utils.GetEnvInt("LOGIN_IP_MAX_FAILURES", 50),
+	}
+}
+
+// LockDuration is how long an account stays locked unless it is unlocked by email.
+func (lg *LoginGuard) LockDuration() time.Duration {
+	return lg.lockFor
+}
+
+// Emails are hashed so the keys hold no addresses; the hash tag keeps an account's keys
+// in the same cluster slot for the scripts above.
+func accountKeys(email string) []string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	account := hex.EncodeToString(sum[:16])
+	return []string{"login:{" + account + "}:failures", "login:{" + account + "}:lock"}
+}
+
+func addressKey(ip string) string {
+	return "login:ip:" + ip
+}
+
+// Begin reserves a login attempt on the account from ip before the password is checked,
+// so concurrent guesses are throttled as if each had already failed. It returns the
+// attempt to pass to Fail, Succeed or Release, or how long the client has to wait and
+// whether that is because the account is locked.
+func (lg *LoginGuard) Begin(ctx context.Context, email, ip string) (string, time.Duration, bool, error) {
+	now := time.Now().UnixMilli()
+	attempt := uuid.NewString()
+
+	address, err := reserveAddressScript.Run(ctx, lg.rdb, []string{addressKey(ip)},
+		now, lg.window.Milliseconds(), attempt, lg.maxPerIP).Int64()
+	if err != nil {
+		return "", 0, false, err
+	}
+	if address > 0 {
+		return "", time.Duration(address) * time.Millisecond, false, nil
+	}
+
+	account, err := reserveAccountScript.Run(ctx, lg.rdb, accountKeys(email),
+		now, lg.window.Milliseconds(), attempt, lg.freeAttempts, lg.baseDelay.Milliseconds(), lg.maxDelay.Milliseconds()).Int64Slice()
+	if err != nil {
+		lg.rdb.ZRem(ctx, addressKey(ip), attempt)
+		return "", 0, false, err
+	}
+	if account[0] > 0 {
+		if err := lg.rdb.ZRem(ctx, addressKey(ip), attempt).Err(); err != nil {
+			return "", 0, false, err
+		}
+		return "", time.Duration(account[0]) * time.Millisecond, account[1] == 1, nil
+	}
+	return attempt, 0, false, nil
+}
+
+// Fail records the attempt as a failed login and reports whether it got the account locked.
+func (lg *LoginGuard) Fail(ctx context.Context, email, ip, attempt string) (bool, error) {
+	now := time.Now().UnixMilli()
+
+	_, err := recordFailureScript.Run(ctx, lg.rdb, []string{addressKey(ip)},
+		now, lg.window.Milliseconds(), attempt, "", 0).Result()
+	if err != nil {
+		return false, err
+	}
+
+	locked, err := recordFailureScript.Run(ctx, lg.rdb, accountKeys(email),
+		now, lg.window.Milliseconds(), attempt, lg.lockAfter, lg.lockFor.Milliseconds()).Int()
+	return locked == 1, err
+}
+
+// Succeed forgets the account's failures after a successful login.
+func (lg *LoginGuard) Succeed(ctx context.Context, email, ip, attempt string) error {
+	if err := lg.rdb.ZRem(ctx, addressKey(ip), attempt).Err(); err != nil {
+		return err
+	}
+	return lg.rdb.Del(ctx, accountKeys(email)[0]).Err()
+}
+
+// Release gives the attempt back when the password could not be checked at all.
+func (lg *LoginGuard) Release(ctx context.Context, email, ip, attempt string) error {
+	if err := lg.rdb.ZRem(ctx, addressKey(ip), attempt).Err(); err != nil {
+		return err
+	}
+	return lg.rdb.ZRem(ctx, accountKeys(email)[0], attempt).Err()
+}
+
+// Unlock lifts a lockout and forgets the account's failures.
+func (lg *LoginGuard) Unlock(ctx context.Context, email string) error {
+	return lg.rdb.Del(ctx, accountKeys(email)...).Err()
+}
@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// The guard's scripts run inside Redis, so these tests need one: REDIS_TEST_ADDR=localhost:6379.
+func testLoginGuard(t *testing.T) *LoginGuard {
+	t.Helper()
+	addr := os.Getenv("REDIS_TEST_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_TEST_ADDR not set")
+	}
+
+	rdb := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { rdb.Close() })
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		t.Fatalf("redis: %v", err)
+	}
+
+	return &LoginGuard{
+		rdb:          rdb,
+		window:       time.Minute,
+		freeAttempts: 2,
+		baseDelay:    time.Millisecond,
+		maxDelay:     time.Millisecond,
+		lockAfter:    4,
+		lockFor:      time.Minute,
+		maxPerIP:     100,
+	}
+}
+
+// testAccount returns an email and address no other test touches.
+func testAccount(t *testing.T, lg *LoginGuard) (string, string) {
+	t.Helper()
+	email := uuid.NewString() + "@example.com"
+	ip := "test:" + uuid.NewString()
+	t.Cleanup(func() {
+		lg.rdb.Del(context.Background(), append(accountKeys(email), addressKey(ip))...)
+	})
+	return email, ip
+}
+
+func begin(t *testing.T, lg *LoginGuard, email, ip string) (string, time.Duration, bool) {
+	t.Helper()
+	attempt, wait, locked, err := lg.Begin(context.Background(), email, ip)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	return attempt, wait, locked
+}
+
+func fail(t *testing.T, lg *LoginGuard, email, ip string) bool {
+	t.Helper()
+	// past the previous failure's delay
+	time.Sleep(5 * time.Millisecond)
+	attempt, wait, _ := begin(t, lg, email, ip)
+	if wait > 0 {
+		t.Fatalf("Begin: wait %s, want none", wait)
+	}
+	locked, err := lg.Fail(context.Background(), email, ip, attempt)
+	if err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+	return locked
+}
+
+func TestLoginGuardDelaysAfterFreeAttempts(t *testing.T) {
+	lg := testLoginGuard(t)
+	lg.maxDelay = time.Hour
+	lg.baseDelay = time.Hour
+	email, ip := testAccount(t, lg)
+
+	fail(t, lg, email, ip)
+	fail(t, lg, email, ip)
+
+	_, wait, locked := begin(t, lg, email, ip)
+	if wait <= 0 || locked {
+		t.Fatalf("after the free attempts: wait %s, locked %v; want a delay", wait, locked)
+	}
+}
+
+func TestLoginGuardForgetsFailuresOutsideWindow(t *testing.T) {
+	lg := testLoginGuard(t)
+	lg.window = 50 * time.Millisecond
+	lg.baseDelay = time.Hour
+	lg.maxDelay = time.Hour
+	email, ip := testAccount(t, lg)
+
+	fail(t, lg, email, ip)
+	fail(t, lg, email, ip)
+	time.Sleep(2 * lg.window)
+
+	if _, wait, _ := begin(t, lg, email, ip); wait > 0 {
+		t.Fatalf("after the window: wait %s, want none", wait)
+	}
+}
+
+func TestLoginGuardReservesConcurrentAttempts(t *testing.T) {
+	lg := testLoginGuard(t)
+	lg.baseDelay = time.Hour
+	lg.maxDelay = time.Hour
+	email, ip := testAccount(t, lg)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	admitted := 0
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attempt, _, _, err := lg.Begin(context.Background(), email, ip)
+			if err != nil {
+				t.Errorf("Begin: %v", err)
+				return
+			}
+			if attempt != "" {
+				mu.Lock()
+				admitted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// in flight attempts count as failures, so only the free ones get through
+	if admitted != lg.freeAttempts {
+		t.Fatalf("admitted %d concurrent attempts, want %d", admitted, lg.freeAttempts)
+	}
+}
+
+func TestLoginGuardReleaseGivesAttemptBack(t *testing.T) {
+	lg := testLoginGuard(t)
+	lg.freeAttempts = 1
+	lg.baseDelay = time.Hour
+	lg.maxDelay = time.Hour
+	email, ip := testAccount(t, lg)
+
+	attempt, _, _ := begin(t, lg, email, ip)
+	if err := lg.Release(context.Background(), email, ip, attempt); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+
+	if _, wait, _ := begin(t, lg, email, ip); wait > 0 {
+		t.Fatalf("after a release: wait %s, want none", wait)
+	}
+}
+
+func TestLoginGuardLocksAccount(t *testing.T) {
+	lg := testLoginGuard(t)
+	email, ip := testAccount(t, lg)
+
+	for i := 1; i < lg.lockAfter; i++ {
+		if fail(t, lg, email, ip) {
+			t.Fatalf("failure %d locked the account, want %d", i, lg.lockAfter)
+		}
+	}
+	if !fail(t, lg, email, ip) {
+		t.Fatalf("failure %d did not lock the account", lg.lockAfter)
+	}
+
+	_, wait, locked := begin(t, lg, email, ip)
+	if !locked || wait <= 0 || wait > lg.lockFor {
+		t.Fatalf("locked account: wait %s, locked %v", wait, locked)
+	}
+}
+
+func TestLoginGuardUnlock(t *testing.T) {
+	lg := testLoginGuard(t)
+	email, ip := testAccount(t, lg)
+
+	for range lg.lockAfter {
+		fail(t, lg, email, ip)
+	}
+	if err := lg.Unlock(context.Background(), email); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+
+	if _, wait, locked := begin(t, lg, email, ip); wait > 0 || locked {
+		t.Fatalf("after unlock: wait %s, locked %v; want none", wait, locked)
+	}
+}
+
+func TestLoginGuardSucceedForgetsFailures(t *testing.T) {
+	lg := testLoginGuard(t)
+	email, ip := testAccount(t, lg)
+
+	fail(t, lg, email, ip)
+	fail(t, lg, email, ip)
+	time.Sleep(5 * time.Millisecond)
+	attempt, _, _ := begin(t, lg, email, ip)
+	if err := lg.Succeed(context.Background(), email, ip, attempt); err != nil {
+		t.Fatalf("Succeed: %v", err)
+	}
+
+	if n := lg.rdb.ZCard(context.Background(), accountKeys(email)[0]).Val(); n != 0 {
+		t.Fatalf("%d failures left after a successful login, want 0", n)
+	}
+}
+
+func TestLoginGuardBlocksAddress(t *testing.T) {
+	lg := testLoginGuard(t)
+	lg.maxPerIP = 3
+	_, ip := testAccount(t, lg)
+
+	for range lg.maxPerIP {
+		email, _ := testAccount(t, lg)
+		fail(t, lg, email, ip)
+	}
+
+	email, _ := testAccount(t, lg)
+	if _, wait, locked := begin(t, lg, email, ip); wait <= 0 || locked {
+		t.Fatalf("blocked address: wait %s, locked %v; want a wait", wait, locked)
+	}
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// A delivery still marked sending after this long was abandoned by a crashed worker.
+const emailSendingTimeout = 10 * time.Minute
+
+const emailColumns = `e.id, e.orders_id, o.reference, e.kind, e.recipient, e.subject, e.status, e.attempts,
+	e.last_error, e.next_attempt_at, e.sent_at, e.created_at, e.updated_at`
+
+type MailRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewMailRepo(db *pgxpool.Pool) *MailRepo {
+	return &MailRepo{db: db}
+}
+
+// queueEmail adds an email for the order to the outbox. It is sent by the mail worker
+// once the surrounding transaction commits; an order gets each kind of email once.
+func queueEmail(ctx context.Context, tx pgx.Tx, orderID int, kind models.EmailKind) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO email_deliveries (orders_id, kind, recipient, status, next_attempt_at, created_at)
+		SELECT id, $2, email, $3, NOW(), NOW() FROM orders WHERE id = $1
+		ON CONFLICT (orders_id, kind) DO NOTHING
+	`, orderID, kind, models.EmailPending)
+	return err
+}
+
+// cancelEmails drops the order's emails that were not sent yet.
+func cancelEmails(ctx context.Context, tx pgx.Tx, orderID int) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE email_deliveries SET status = $2, updated_at = NOW()
+		WHERE orders_id = $1 AND status IN ($3, $4)
+	`, orderID, models.EmailCancelled, models.EmailPending, models.EmailSending)
+	return err
+}
+
+func scanEmails(rows pgx.Rows) ([]models.EmailDelivery, error) {
+	defer rows.Close()
+
+	emails := []models.EmailDelivery{}
+	for rows.Next() {
+		var e models.EmailDelivery
+		err := rows.Scan(&e.ID, &e.OrderID, &e.Reference, &e.Kind, &e.Recipient, &e.Subject, &e.Status, &e.Attempts,
+			&e.LastError, &e.NextAttemptAt, &e.SentAt, &e.CreatedAt, &e.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, e)
+	}
+	return emails, rows.Err()
+}
+
+// ClaimDue marks up to limit due emails as sending and counts the attempt.
+func (mr *MailRepo) ClaimDue(ctx context.Context, limit int) ([]models.EmailDelivery, error) {
+	rows, err := mr.db.Query(ctx, `
+		WITH due AS (
+			SELECT id FROM email_deliveries
+			WHERE (status = $1 AND next_attempt_at <= NOW())
+			   OR (status = $2 AND updated_at < NOW() - make_interval(secs => $3))
+			ORDER BY next_attempt_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE email_deliveries e SET status = $2, attempts = e.attempts + 1, updated_at = NOW()
+			FROM due
+			WHERE e.id = due.id
+			RETURNING e.*
+		)
+		SELECT `+emailColumns+`
+		FROM claimed e
+		JOIN orders o ON o.id = e.orders_id
+		ORDER BY e.next_attempt_at
+	`, models.EmailPending, models.EmailSending, emailSendingTimeout.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanEmails(rows)
+}
+
+func (mr *MailRepo) MarkSent(ctx context.Context, id int, subject string) error {
+	_, err := mr.db.Exec(ctx, `
+		UPDATE email_deliveries SET status = $2, subject = $3, last_error = NULL, sent_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, id, models.EmailSent, subject)
+	return err
+}
+
+// MarkCancelled drops a claimed email that is no longer wanted, noting why.
+func (mr *MailRepo) MarkCancelled(ctx context.Context, id int, reason string) error {
+	_, err := mr.db.Exec(ctx, `
+		UPDATE email_deliveries SET status = $2, last_error = $3, updated_at = NOW()
+		WHERE id = $1
+	`, id, models.EmailCancelled, reason)
+	return err
+}
+
+// MarkFailed records a failed attempt. With retryAt set the email is tried again then,
+// otherwise it is given up on.
+func (mr *MailRepo) MarkFailed(ctx context.Context, id int, cause string, retryAt *time.Time) error {
+	status := models.EmailFailed
+	next := time.Now()
+	if retryAt != nil {
+		status = models.EmailPending
+		next = *retryAt
+	}
+
+	_, err := mr.db.Exec(ctx, `
+		UPDATE email_deliveries SET status = $2, last_error = $3, next_attempt_at = $4, updated_at = NOW()
+		WHERE id = $1
+	`, id, status, cause, next)
+	return err
+}
+
+func (mr *MailRepo) GetOrderEmails(ctx context.Context, orderID int) ([]models.EmailDelivery, error) {
+	rows, err := mr.db.Query(ctx, `
+		SELECT `+emailColumns+`
+		FROM email_deliveries e
+		JOIN orders o ON o.id = e.orders_id
+		WHERE e.orders_id = $1
+		ORDER BY e.created_at
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return scanEmails(rows)
+}
+
+// ResendOrderEmail queues the order's email again, starting a fresh round of attempts.
+// It returns ErrOrderNotFound when the order never had that email.
+func (mr *MailRepo) ResendOrderEmail(ctx context.Context, orderID int, kind models.EmailKind) error {
+	tag, err := mr.db.Exec(ctx, `
+		UPDATE email_deliveries e SET status = $3, attempts = 0, last_error = NULL, next_attempt_at = NOW(),
+		       recipient = o.email, updated_at = NOW()
+		FROM orders o
+		WHERE o.id = e.orders_id AND e.orders_id = $1 AND e.kind = $2
+	`, orderID, kind, models.EmailPending)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrOrderNotFound
+	}
+	return nil
+}
@@ -2,77 +2,786 @@ package repositories
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/payments"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/Darari17/be-tickitz-full/pkg"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var (
+	ErrOrderNotFound    = errors.New("order not found")
+	ErrScheduleNotFound = errors.New("schedule not found")
+)
+
+// InvalidTransitionError is returned when an order status change is not allowed by the state machine.
+type InvalidTransitionError struct {
+	From models.OrderStatus
+	To   models.OrderStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("cannot change order status from %s to %s", e.From, e.To)
+}
+
+// CancelRejectedError explains why an order or some of its seats cannot be cancelled.
+type CancelRejectedError struct {
+	Reason    string
+	Message   string
+	SeatCodes []string
+}
+
+func (e *CancelRejectedError) Error() string {
+	return e.Reason + ": " + e.Message
+}
+
+// SeatSelectionError is returned by ValidateSeatSelection for a selection that breaks the
+// seat rules. Reason is a stable machine readable code; SeatCodes lists the seats at fault.
+type SeatSelectionError struct {
+	Reason    string
+	Message   string
+	SeatCodes []string
+}
+
+func (e *SeatSelectionError) Error() string {
+	return e.Reason + ": " + e.Message
+}
+
+// SeatTakenError is returned by CreateOrder when a seat was already sold for the schedule.
+type SeatTakenError struct {
+	SeatCodes []string
+}
+
+func (e *SeatTakenError) Error() string {
+	return "seat already taken: " + strings.Join(e.SeatCodes, ", ")
+}
+
+// ChargeError is returned by CreateOrder when the payment provider could not create the
+// charge. Nothing of the order is kept.
+type ChargeError struct {
+	Err error
+}
+
+func (e *ChargeError) Error() string {
+	return "create charge: " + e.Err.Error()
+}
+
+func (e *ChargeError) Unwrap() error {
+	return e.Err
+}
+
+// querier is satisfied by both *pgxpool.Pool and pgx.Tx.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 type OrderRepo struct {
-	db *pgxpool.Pool
+	db           *pgxpool.Pool
+	holds        *SeatHoldRepo
+	serviceFee   int64
+	cancelCutoff time.Duration
 }
 
-func NewOrderRepo(db *pgxpool.Pool) *OrderRepo {
-	return &OrderRepo{db: db}
+func NewOrderRepo(db *pgxpool.Pool, holds *SeatHoldRepo) *OrderRepo {
+	return &OrderRepo{
+		db:           db,
+		holds:        holds,
+		serviceFee:   int64(utils.GetEnvInt("ORDER_SERVICE_FEE", 0)),
+		cancelCutoff: utils.GetEnvDuration("ORDER_CANCEL_CUTOFF", 2*time.Hour),
+	}
 }
 
-func (or *OrderRepo) CreateOrder(ctx context.Context, order *models.Order, seatIDs []int) (*models.Order, error) {
+// CancelOptions describes who cancels what. UserID limits the cancellation to the
+// owner's orders and Override skips the cancellation cutoff; both are for admins.
+type CancelOptions struct {
+	UserID      *uuid.UUID
+	RequestedBy uuid.UUID
+	SeatCodes   []string
+	Reason      string
+	Override    bool
+}
+
+// CreateOrder stores a pending order for the seats and charges it with provider. The
+// charge is created before the order commits, so a failed charge leaves no order behind
+// holding the seats.
+func (or *OrderRepo) CreateOrder(ctx context.Context, order *models.Order, seatIDs []int, provider payments.Provider) (*models.Order, *payments.Charge, error) {
 	tx, err := or.db.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer tx.Rollback(ctx)
 
-	if order.QRCode == "" {
-		order.QRCode = fmt.Sprintf("QR-%d", time.Now().Unix())
+	quote, err := or.quotePrice(ctx, tx, order.ScheduleID, seatIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seatCodes := map[int]string{}
+	seatPrices := map[int]int64{}
+	for _, line := range quote.Lines {
+		seatCodes[line.SeatID] = line.SeatCode
+		seatPrices[line.SeatID] = line.Price
+		order.Seats = append(order.Seats, models.Seat{
+			ID:        line.SeatID,
+			SeatCode:  line.SeatCode,
+			SeatClass: line.SeatClass,
+			Price:     line.Price,
+		})
+	}
+	var promo *models.PromoCode
+	var promoDiscount int64
+	if order.PromoCode != "" {
+		promo, err = findPromo(ctx, tx, order.PromoCode, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		err = checkPromo(ctx, tx, promo, promoOrder{userID: order.UserID, scheduleID: order.ScheduleID, paymentID: order.PaymentID, tickets: len(quote.Lines)})
+		if err != nil {
+			return nil, nil, err
+		}
+		promoDiscount = promo.DiscountFor(quote.Subtotal)
+		quote.Discount += promoDiscount
+		order.PromoCode = promo.Code
+	}
+
+	// Redeemed points discount the tickets only, never the fees.
+	if order.PointsRedeemed > 0 {
+		rules := loadPointRules()
+		order.PointsRedeemed = min(order.PointsRedeemed, int((quote.Subtotal-quote.Discount)/rules.value))
+		quote.Discount += int64(order.PointsRedeemed) * rules.value
+	}
+	quote.Total = quote.Subtotal + quote.Fees - quote.Discount
+
+	order.Subtotal = quote.Subtotal
+	order.Fees = quote.Fees
+	order.Discount = quote.Discount
+	order.Total = quote.Total
+
+	taken, err := or.takenSeatCodes(ctx, tx, order.ScheduleID, seatIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(taken) > 0 {
+		return nil, nil, &SeatTakenError{SeatCodes: taken}
 	}
 
 	query := `
-        INSERT INTO orders (qr_code, users_id, schedules_id, payments_id, fullname, email, phone_number, created_at)
-        VALUES ($1,$2,$3,$4,$5,$6,$7,NOW())
-        RETURNING id, created_at
+        INSERT INTO orders (reference, qr_code, users_id, schedules_id, payments_id, fullname, email, phone_number, status,
+                            subtotal, fees, discount, total, points_redeemed, created_at)
+        VALUES ($1,'',$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,NOW())
+        RETURNING id, status, created_at
     `
+	order.Reference, err = newOrderReference()
+	if err != nil {
+		return nil, nil, err
+	}
+
 	err = tx.QueryRow(ctx, query,
-		order.QRCode, order.UserID, order.ScheduleID, order.PaymentID,
-		order.FullName, order.Email, order.Phone,
-	).Scan(&order.ID, &order.CreatedAt)
+		order.Reference, order.UserID, order.ScheduleID, order.PaymentID,
+		order.FullName, order.Email, order.Phone, models.OrderStatusPending,
+		order.Subtotal, order.Fees, order.Discount, order.Total, order.PointsRedeemed,
+	).Scan(&order.ID, &order.Status, &order.CreatedAt)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if err := addPoints(ctx, tx, order.UserID, order.ID, models.PointRedeem, -order.PointsRedeemed, "Redeemed on order"); err != nil {
+		return nil, nil, err
+	}
+
+	if promo != nil {
+		if err := redeemPromo(ctx, tx, promo, order.ID, order.UserID, promoDiscount); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := insertStatusHistory(ctx, tx, order.ID, nil, order.Status, ""); err != nil {
+		return nil, nil, err
 	}
 
 	for _, seatID := range seatIDs {
-		_, err := tx.Exec(ctx, `INSERT INTO order_seats (orders_id, seats_id) VALUES ($1,$2)`, order.ID, seatID)
+		_, err := tx.Exec(ctx, `INSERT INTO order_seats (orders_id, seats_id, schedules_id, price) VALUES ($1,$2,$3,$4)`, order.ID, seatID, order.ScheduleID, seatPrices[seatID])
 		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "order_seats_schedule_seat_key" {
+				return nil, nil, &SeatTakenError{SeatCodes: []string{seatCodes[seatID]}}
+			}
+			return nil, nil, err
+		}
+	}
+
+	charge, err := provider.CreateCharge(ctx, payments.ChargeRequest{
+		OrderID:     order.ID,
+		Amount:      order.Total,
+		Email:       order.Email,
+		Description: fmt.Sprintf("Tickitz order #%d", order.ID),
+	})
+	if err != nil {
+		return nil, nil, &ChargeError{Err: err}
+	}
+	if _, err := insertPaymentTransaction(ctx, tx, order.ID, charge); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+	return order, charge, nil
+}
+
+// referenceAlphabet is Crockford's base32, which leaves out letters easily misread as digits.
+const referenceAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newOrderReference returns the random public code that identifies an order in URLs.
+func newOrderReference() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = referenceAlphabet[b%32]
+	}
+	return string(buf), nil
+}
+
+// GetOrderIDByReference resolves the public reference of an order.
+func (or *OrderRepo) GetOrderIDByReference(ctx context.Context, reference string) (int, error) {
+	var orderID int
+	err := or.db.QueryRow(ctx, `SELECT id FROM orders WHERE reference = $1`, reference).Scan(&orderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrOrderNotFound
+		}
+		return 0, err
+	}
+	return orderID, nil
+}
+
+// issueTicket signs the ticket token of an order that was just paid and stores it as the
+// order's QR code. The ticket stays valid until the end of the show day.
+func issueTicket(ctx context.Context, tx pgx.Tx, orderID int) error {
+	var scheduleID int
+	var showDate time.Time
+	var seatCodes []string
+	err := tx.QueryRow(ctx, `
+		SELECT o.schedules_id, s.date,
+		       ARRAY(SELECT se.seat_code FROM order_seats os JOIN seats se ON se.id = os.seats_id
+		             WHERE os.orders_id = o.id AND os.released_at IS NULL ORDER BY se.seat_code)
+		FROM orders o
+		JOIN schedules s ON s.id = o.schedules_id
+		WHERE o.id = $1
+	`, orderID).Scan(&scheduleID, &showDate, &seatCodes)
+	if err != nil {
+		return err
+	}
+
+	token, err := pkg.NewTicketClaims(orderID, scheduleID, seatCodes, showDate.AddDate(0, 0, 1)).GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE orders SET qr_code = $1 WHERE id = $2`, token, orderID)
+	return err
+}
+
+func (or *OrderRepo) QuotePrice(ctx context.Context, scheduleID int, seatIDs []int) (*models.PriceQuote, error) {
+	return or.quotePrice(ctx, or.db, scheduleID, seatIDs)
+}
+
+// QuotePromo prices the seats with the promo code applied, checking the code the same way
+// checkout does without using it up.
+func (or *OrderRepo) QuotePromo(ctx context.Context, code string, userID uuid.UUID, scheduleID, paymentID int, seatIDs []int) (*models.PriceQuote, error) {
+	quote, err := or.quotePrice(ctx, or.db, scheduleID, seatIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	promo, err := findPromo(ctx, or.db, code, false)
+	if err != nil {
+		return nil, err
+	}
+	err = checkPromo(ctx, or.db, promo, promoOrder{userID: userID, scheduleID: scheduleID, paymentID: paymentID, tickets: len(quote.Lines)})
+	if err != nil {
+		return nil, err
+	}
+
+	quote.Discount += promo.DiscountFor(quote.Subtotal)
+	quote.Total = quote.Subtotal + quote.Fees - quote.Discount
+	quote.PromoCode = promo.Code
+	return quote, nil
+}
+
+// quotePrice prices every seat as the schedule (or cinema) base price plus the weekend,
+// time slot and seat class surcharges, then adds the per ticket service fee.
+func (or *OrderRepo) quotePrice(ctx context.Context, q querier, scheduleID int, seatIDs []int) (*models.PriceQuote, error) {
+	var basePrice, weekendSurcharge, timeSurcharge int64
+	var date time.Time
+	err := q.QueryRow(ctx, `
+		SELECT COALESCE(s.base_price, c.base_price), c.weekend_surcharge, t.surcharge, s.date
+		FROM schedules s
+		JOIN cinemas c ON c.id = s.cinemas_id
+		JOIN times t ON t.id = s.times_id
+		WHERE s.id = $1
+	`, scheduleID).Scan(&basePrice, &weekendSurcharge, &timeSurcharge, &date)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+
+	quote := &models.PriceQuote{ScheduleID: scheduleID, DayType: "weekday"}
+	var daySurcharge int64
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		quote.DayType = "weekend"
+		daySurcharge = weekendSurcharge
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT se.id, se.seat_code, se.seat_class, sc.surcharge
+		FROM seats se
+		JOIN seat_classes sc ON sc.code = se.seat_class
+		WHERE se.id = ANY($1)
+		ORDER BY se.id
+	`, seatIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		line := models.PriceLine{
+			BasePrice:     basePrice,
+			DaySurcharge:  daySurcharge,
+			TimeSurcharge: timeSurcharge,
+		}
+		if err := rows.Scan(&line.SeatID, &line.SeatCode, &line.SeatClass, &line.SeatSurcharge); err != nil {
 			return nil, err
 		}
+		line.Price = line.BasePrice + line.DaySurcharge + line.TimeSurcharge + line.SeatSurcharge
+		quote.Lines = append(quote.Lines, line)
+		quote.Subtotal += line.Price
+	}
+
+	quote.Fees = or.serviceFee * int64(len(quote.Lines))
+	quote.Total = quote.Subtotal + quote.Fees - quote.Discount
+	return quote, nil
+}
+
+// ExpirePendingOrders expires up to limit orders that stayed pending for longer than
+// window and releases their seats. Orders locked by a concurrent payment are skipped
+// and picked up on the next run.
+func (or *OrderRepo) ExpirePendingOrders(ctx context.Context, window time.Duration, limit int) ([]models.ExpiredOrder, error) {
+	tx, err := or.db.Begin(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback(ctx)
 
-	rows, err := tx.Query(ctx, `SELECT id, seat_code FROM seats WHERE id = ANY($1)`, seatIDs)
+	rows, err := tx.Query(ctx, `
+		SELECT id, reference, users_id, schedules_id
+		FROM orders
+		WHERE status = $1 AND created_at < NOW() - make_interval(secs => $2)
+		ORDER BY created_at
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	`, models.OrderStatusPending, window.Seconds(), limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	var expired []models.ExpiredOrder
 	for rows.Next() {
-		var seat models.Seat
-		if err := rows.Scan(&seat.ID, &seat.SeatCode); err != nil {
+		var o models.ExpiredOrder
+		if err := rows.Scan(&o.OrderID, &o.Reference, &o.UserID, &o.ScheduleID); err != nil {
+			return nil, err
+		}
+		expired = append(expired, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range expired {
+		o := &expired[i]
+		err := tx.QueryRow(ctx, `
+			SELECT COALESCE(array_agg(se.seat_code ORDER BY se.seat_code), '{}')
+			FROM order_seats os
+			JOIN seats se ON se.id = os.seats_id
+			WHERE os.orders_id = $1 AND os.released_at IS NULL
+		`, o.OrderID).Scan(&o.SeatCodes)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := transitionStatus(ctx, tx, o.OrderID, models.OrderStatusPending, models.OrderStatusExpired, "Payment window elapsed"); err != nil {
 			return nil, err
 		}
-		order.Seats = append(order.Seats, seat)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
-	return order, nil
+	return expired, nil
+}
+
+// UpdateStatus moves an order along the status state machine and records the transition.
+func (or *OrderRepo) UpdateStatus(ctx context.Context, orderID int, to models.OrderStatus, note string) error {
+	tx, err := or.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var from models.OrderStatus
+	if err := tx.QueryRow(ctx, `SELECT status FROM orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&from); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrOrderNotFound
+		}
+		return err
+	}
+
+	if err := transitionStatus(ctx, tx, orderID, from, to, note); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-func (or *OrderRepo) GetSeatIDsByCodes(ctx context.Context, seatCodes []string) ([]int, error) {
-	rows, err := or.db.Query(ctx, `SELECT id FROM seats WHERE seat_code = ANY($1)`, seatCodes)
+// CancelOrder releases some or all seats of an order. Cancelling every remaining seat
+// moves the order to cancelled. Paid orders get a pending refund of the cancelled seats
+// which the caller settles with the payment provider.
+func (or *OrderRepo) CancelOrder(ctx context.Context, orderID int, opts CancelOptions) (*models.Cancellation, error) {
+	tx, err := or.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		ownerID  uuid.UUID
+		status   models.OrderStatus
+		total    int64
+		startsAt time.Time
+		now      time.Time
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT o.users_id, o.status, o.total, s.date + t.time::time, LOCALTIMESTAMP
+		FROM orders o
+		JOIN schedules s ON s.id = o.schedules_id
+		JOIN times t ON t.id = s.times_id
+		WHERE o.id = $1
+		FOR UPDATE OF o
+	`, orderID).Scan(&ownerID, &status, &total, &startsAt, &now)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	if opts.UserID != nil && *opts.UserID != ownerID {
+		return nil, ErrOrderNotFound
+	}
+
+	if status != models.OrderStatusPending && status != models.OrderStatusPaid {
+		return nil, &CancelRejectedError{Reason: "order_" + string(status), Message: "Order is " + string(status)}
+	}
+	if !opts.Override && now.After(startsAt.Add(-or.cancelCutoff)) {
+		return nil, &CancelRejectedError{Reason: "cutoff_passed", Message: "Cancellation closed at " + startsAt.Add(-or.cancelCutoff).Format("2006-01-02 15:04")}
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT se.id, se.seat_code, os.price, os.admitted_at IS NOT NULL
+		FROM order_seats os
+		JOIN seats se ON se.id = os.seats_id
+		WHERE os.orders_id = $1 AND os.released_at IS NULL
+		ORDER BY se.seat_code
+		FOR UPDATE OF os
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type orderSeat struct {
+		id       int
+		price    int64
+		admitted bool
+	}
+	seats := map[string]orderSeat{}
+	var active []string
+	for rows.Next() {
+		var code string
+		var seat orderSeat
+		if err := rows.Scan(&seat.id, &code, &seat.price, &seat.admitted); err != nil {
+			return nil, err
+		}
+		seats[code] = seat
+		active = append(active, code)
+	}
+	rows.Close()
+
+	seatCodes := opts.SeatCodes
+	if len(seatCodes) == 0 {
+		seatCodes = active
+	}
+
+	var unknown, admitted []string
+	for _, code := range seatCodes {
+		seat, ok := seats[code]
+		switch {
+		case !ok:
+			unknown = append(unknown, code)
+		case seat.admitted:
+			admitted = append(admitted, code)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, &CancelRejectedError{Reason: "seat_not_on_order", Message: "Seats are not part of this order", SeatCodes: unknown}
+	}
+	if len(admitted) > 0 {
+		return nil, &CancelRejectedError{Reason: "seat_admitted", Message: "Seats were already admitted", SeatCodes: admitted}
+	}
+
+	full := len(seatCodes) == len(active)
+	if !full && status == models.OrderStatusPending {
+		return nil, &CancelRejectedError{Reason: "partial_unpaid", Message: "Unpaid orders can only be cancelled as a whole"}
+	}
+
+	cancellation := models.Cancellation{OrderID: orderID, Status: status, SeatCodes: seatCodes}
+
+	var seatIDs []int
+	var amount int64
+	for _, code := range seatCodes {
+		seatIDs = append(seatIDs, seats[code].id)
+		amount += seats[code].price
+	}
+
+	if full {
+		if err := transitionStatus(ctx, tx, orderID, status, models.OrderStatusCancelled, opts.Reason); err != nil {
+			return nil, err
+		}
+		cancellation.Status = models.OrderStatusCancelled
+	} else {
+		if _, err := tx.Exec(ctx, `UPDATE order_seats SET released_at = NOW() WHERE orders_id = $1 AND seats_id = ANY($2)`, orderID, seatIDs); err != nil {
+			return nil, err
+		}
+		if err := reversePoints(ctx, tx, orderID, len(seatCodes), "Seats cancelled"); err != nil {
+			return nil, err
+		}
+
+		note := "Seats cancelled: " + strings.Join(seatCodes, ", ")
+		if opts.Reason != "" {
+			note += " (" + opts.Reason + ")"
+		}
+		if err := insertStatusHistory(ctx, tx, orderID, &status, status, note); err != nil {
+			return nil, err
+		}
+	}
+
+	if status == models.OrderStatusPaid {
+		var refunded int64
+		err := tx.QueryRow(ctx, `
+			SELECT COALESCE(SUM(amount), 0) FROM order_refunds WHERE orders_id = $1 AND status <> $2
+		`, orderID, models.RefundFailed).Scan(&refunded)
+		if err != nil {
+			return nil, err
+		}
+
+		// The last cancellation refunds whatever is left, fees included.
+		if full {
+			amount = total - refunded
+		}
+		amount = max(min(amount, total-refunded), 0)
+
+		refund, err := insertRefund(ctx, tx, orderID, amount, seatCodes, opts)
+		if err != nil {
+			return nil, err
+		}
+		cancellation.Refund = refund
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &cancellation, nil
+}
+
+func insertRefund(ctx context.Context, tx pgx.Tx, orderID int, amount int64, seatCodes []string, opts CancelOptions) (*models.OrderRefund, error) {
+	r := models.OrderRefund{
+		OrderID:     orderID,
+		Amount:      amount,
+		SeatCodes:   seatCodes,
+		Status:      models.RefundPending,
+		RequestedBy: opts.RequestedBy,
+	}
+	if opts.Reason != "" {
+		r.Reason = &opts.Reason
+	}
+
+	err := tx.QueryRow(ctx, `
+		SELECT provider, reference
+		FROM payment_transactions
+		WHERE orders_id = $1 AND status = 'paid'
+		ORDER BY id DESC
+		LIMIT 1
+	`, orderID).Scan(&r.Provider, &r.ChargeReference)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if r.Provider == nil || amount == 0 {
+		r.Status = models.RefundManual
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO order_refunds (orders_id, provider, charge_reference, amount, seat_codes, reason, status, requested_by, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,NOW())
+		RETURNING id, created_at
+	`, r.OrderID, r.Provider, r.ChargeReference, r.Amount, r.SeatCodes, r.Reason, r.Status, r.RequestedBy).Scan(&r.ID, &r.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// transitionStatus expects the order row to be locked by the caller.
+func transitionStatus(ctx context.Context, tx pgx.Tx, orderID int, from, to models.OrderStatus, note string) error {
+	if !from.CanTransitionTo(to) {
+		return &InvalidTransitionError{From: from, To: to}
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE orders SET status = $1, updated_at = NOW() WHERE id = $2`, to, orderID); err != nil {
+		return err
+	}
+
+	if err := insertStatusHistory(ctx, tx, orderID, &from, to, note); err != nil {
+		return err
+	}
+
+	if to == models.OrderStatusPaid {
+		if err := issueTicket(ctx, tx, orderID); err != nil {
+			return err
+		}
+		if err := accruePoints(ctx, tx, orderID); err != nil {
+			return err
+		}
+		if err := queueEmail(ctx, tx, orderID, models.EmailTicket); err != nil {
+			return err
+		}
+		if err := queueReminders(ctx, tx, orderID); err != nil {
+			return err
+		}
+	}
+
+	if to.ReleasesSeats() {
+		if _, err := tx.Exec(ctx, `UPDATE order_seats SET released_at = NOW() WHERE orders_id = $1 AND released_at IS NULL`, orderID); err != nil {
+			return err
+		}
+
+		note := "Order " + string(to)
+		if from == models.OrderStatusPaid || from == models.OrderStatusCheckedIn {
+			if err := reversePoints(ctx, tx, orderID, 0, note); err != nil {
+				return err
+			}
+		}
+		if err := restorePoints(ctx, tx, orderID, note); err != nil {
+			return err
+		}
+		if err := releasePromo(ctx, tx, orderID); err != nil {
+			return err
+		}
+		if err := cancelReminders(ctx, tx, orderID); err != nil {
+			return err
+		}
+		if err := cancelEmails(ctx, tx, orderID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertStatusHistory(ctx context.Context, tx pgx.Tx, orderID int, from *models.OrderStatus, to models.OrderStatus, note string) error {
+	var notePtr *string
+	if note != "" {
+		notePtr = &note
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO order_status_history (orders_id, from_status, to_status, note, created_at)
+		VALUES ($1,$2,$3,$4,NOW())
+	`, orderID, from, to, notePtr)
+	return err
+}
+
+func (or *OrderRepo) getStatusHistory(ctx context.Context, orderIDs []int) (map[int][]models.OrderStatusHistory, error) {
+	rows, err := or.db.Query(ctx, `
+		SELECT id, orders_id, from_status, to_status, note, created_at
+		FROM order_status_history
+		WHERE orders_id = ANY($1)
+		ORDER BY created_at, id
+	`, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := map[int][]models.OrderStatusHistory{}
+	for rows.Next() {
+		var h models.OrderStatusHistory
+		if err := rows.Scan(&h.ID, &h.OrderID, &h.FromStatus, &h.ToStatus, &h.Note, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		history[h.OrderID] = append(history[h.OrderID], h)
+	}
+	return history, nil
+}
+
+func (or *OrderRepo) takenSeatCodes(ctx context.Context, tx pgx.Tx, scheduleID int, seatIDs []int) ([]string, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT se.seat_code
+		FROM order_seats os
+		JOIN seats se ON se.id = os.seats_id
+		WHERE os.schedules_id = $1 AND os.seats_id = ANY($2) AND os.released_at IS NULL
+		ORDER BY se.id
+	`, scheduleID, seatIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var codes []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// GetSeatIDsByCodes maps seat codes to the seats of the schedule's auditorium.
+func (or *OrderRepo) GetSeatIDsByCodes(ctx context.Context, scheduleID int, seatCodes []string) ([]int, error) {
+	rows, err := or.db.Query(ctx, `
+		SELECT se.id
+		FROM seats se
+		JOIN schedules s ON s.auditoriums_id = se.auditoriums_id
+		WHERE s.id = $1 AND se.seat_code = ANY($2)
+	`, scheduleID, seatCodes)
 	if err != nil {
 		return nil, err
 	}
@@ -89,9 +798,33 @@ func (or *OrderRepo) GetSeatIDsByCodes(ctx context.Context, seatCodes []string)
 	return ids, nil
 }
 
+func (or *OrderRepo) GetTakenSeatCodes(ctx context.Context, scheduleID int, seatCodes []string) ([]string, error) {
+	rows, err := or.db.Query(ctx, `
+		SELECT se.seat_code
+		FROM order_seats os
+		JOIN seats se ON se.id = os.seats_id
+		WHERE os.schedules_id = $1 AND se.seat_code = ANY($2) AND os.released_at IS NULL
+		ORDER BY se.id
+	`, scheduleID, seatCodes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var codes []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
 func (or *OrderRepo) GetSchedules(ctx context.Context, movieID int) ([]models.Schedule, error) {
 	rows, err := or.db.Query(ctx, `
-		SELECT id, movies_id, cinemas_id, times_id, locations_id, date
+		SELECT id, movies_id, cinemas_id, times_id, locations_id, auditoriums_id, date
 FROM schedules 
 WHERE movies_id=$1
 
@@ -105,7 +838,7 @@ WHERE movies_id=$1
 	var schedules []models.Schedule
 	for rows.Next() {
 		var s models.Schedule
-		if err := rows.Scan(&s.ID, &s.MovieID, &s.CinemaID, &s.TimeID, &s.LocationID, &s.Date); err != nil {
+		if err := rows.Scan(&s.ID, &s.MovieID, &s.CinemaID, &s.TimeID, &s.LocationID, &s.AuditoriumID, &s.Date); err != nil {
 			return nil, err
 		}
 		schedules = append(schedules, s)
@@ -113,43 +846,196 @@ WHERE movies_id=$1
 	return schedules, nil
 }
 
-func (or *OrderRepo) GetAvailableSeats(ctx context.Context, scheduleID int) ([]models.Seat, error) {
+// GetAvailableSeats returns the seat map of the schedule's auditorium with the status of
+// every cell: sold, held by someone in checkout, available, or a gap without a seat.
+func (or *OrderRepo) GetAvailableSeats(ctx context.Context, scheduleID int) (*models.SeatLayout, error) {
+	return or.seatLayout(ctx, scheduleID, uuid.Nil)
+}
+
+// seatLayout builds the seat map as seen by viewer, whose own holds show as available.
+func (or *OrderRepo) seatLayout(ctx context.Context, scheduleID int, viewer uuid.UUID) (*models.SeatLayout, error) {
+	layout := models.SeatLayout{ScheduleID: scheduleID}
+	err := or.db.QueryRow(ctx, `
+		SELECT a.id, a.name, a.row_count, a.column_count
+		FROM schedules s
+		JOIN auditoriums a ON a.id = s.auditoriums_id
+		WHERE s.id = $1
+	`, scheduleID).Scan(&layout.AuditoriumID, &layout.Auditorium, &layout.Rows, &layout.Columns)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+
 	rows, err := or.db.Query(ctx, `
-		SELECT s.id, s.seat_code
-		FROM seats s
-		WHERE s.id NOT IN (
-			SELECT os.seats_id
-			FROM orders o
-			JOIN order_seats os ON o.id = os.orders_id
-			WHERE o.schedules_id = $1
-		)
-		ORDER BY s.id
-	`, scheduleID)
+		SELECT se.seat_code, se.seat_class, se.row_number, se.column_number,
+		       EXISTS (
+		           SELECT 1 FROM order_seats os
+		           WHERE os.schedules_id = $1 AND os.seats_id = se.id AND os.released_at IS NULL
+		       )
+		FROM seats se
+		WHERE se.auditoriums_id = $2
+	`, scheduleID, layout.AuditoriumID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var seats []models.Seat
+	layout.Grid = models.NewSeatGrid(layout.Rows, layout.Columns)
+
+	var codes []string
 	for rows.Next() {
-		var seat models.Seat
-		if err := rows.Scan(&seat.ID, &seat.SeatCode); err != nil {
+		var cell models.SeatLayoutCell
+		var rowNumber int
+		var sold bool
+		if err := rows.Scan(&cell.SeatCode, &cell.SeatClass, &rowNumber, &cell.Column, &sold); err != nil {
 			return nil, err
 		}
-		seats = append(seats, seat)
+		if rowNumber < 1 || rowNumber > layout.Rows || cell.Column < 1 || cell.Column > layout.Columns {
+			continue
+		}
+
+		cell.Status = models.SeatAvailable
+		if sold {
+			cell.Status = models.SeatSold
+		} else {
+			codes = append(codes, cell.SeatCode)
+		}
+		layout.Grid[rowNumber-1].Cells[cell.Column-1] = cell
 	}
-	return seats, nil
+	rows.Close()
+
+	holders, err := or.holds.Holders(ctx, scheduleID, codes)
+	if err != nil {
+		return nil, err
+	}
+	for r := range layout.Grid {
+		for c, cell := range layout.Grid[r].Cells {
+			if holder, held := holders[cell.SeatCode]; held && holder != viewer.String() && cell.Status == models.SeatAvailable {
+				layout.Grid[r].Cells[c].Status = models.SeatHeld
+			}
+		}
+	}
+
+	classRows, err := or.db.Query(ctx, `
+		SELECT DISTINCT sc.code, sc.name, sc.surcharge
+		FROM seat_classes sc
+		JOIN seats se ON se.seat_class = sc.code
+		WHERE se.auditoriums_id = $1
+		ORDER BY sc.surcharge, sc.code
+	`, layout.AuditoriumID)
+	if err != nil {
+		return nil, err
+	}
+	defer classRows.Close()
+
+	layout.Classes = []models.SeatClass{}
+	for classRows.Next() {
+		var class models.SeatClass
+		if err := classRows.Scan(&class.Code, &class.Name, &class.Surcharge); err != nil {
+			return nil, err
+		}
+		layout.Classes = append(layout.Classes, class)
+	}
+	return &layout, classRows.Err()
+}
+
+// ValidateSeatSelection checks the seats a user is about to buy against the seat map of
+// the schedule. Couple seats are sold in pairs, companion seats only together with the
+// wheelchair space beside them, and a selection may not leave a single free seat
+// stranded between taken ones. Seats held by other users count as taken; the user's own
+// holds outside the selection count as free.
+func (or *OrderRepo) ValidateSeatSelection(ctx context.Context, scheduleID int, userID uuid.UUID, seatCodes []string) error {
+	layout, err := or.seatLayout(ctx, scheduleID, userID)
+	if err != nil {
+		return err
+	}
+
+	selected := map[[2]int]bool{}
+	var unknown []string
+	for _, code := range seatCodes {
+		r, c, ok := layout.Find(code)
+		if !ok {
+			unknown = append(unknown, code)
+			continue
+		}
+		selected[[2]int{r, c}] = true
+	}
+	if len(unknown) > 0 {
+		return &SeatSelectionError{Reason: "unknown_seat", Message: "Seats do not exist in this auditorium", SeatCodes: unknown}
+	}
+
+	var unpaired, unaccompanied []string
+	for pos := range selected {
+		r, c := pos[0], pos[1]
+		cell := layout.Grid[r].Cells[c]
+		switch cell.SeatClass {
+		case models.SeatClassCouple:
+			if partner, ok := layout.CouplePartner(r, c); ok && !selected[[2]int{r, partner}] {
+				unpaired = append(unpaired, layout.Grid[r].Cells[partner].SeatCode)
+			}
+		case models.SeatClassCompanion:
+			if !selectedWheelchairBeside(layout, selected, r, c) {
+				unaccompanied = append(unaccompanied, cell.SeatCode)
+			}
+		}
+	}
+	if len(unpaired) > 0 {
+		slices.Sort(unpaired)
+		return &SeatSelectionError{Reason: "couple_pair", Message: "Couple seats must be booked together with their partner seat", SeatCodes: unpaired}
+	}
+	if len(unaccompanied) > 0 {
+		slices.Sort(unaccompanied)
+		return &SeatSelectionError{Reason: "companion_seat", Message: "Companion seats can only be booked with the wheelchair space beside them", SeatCodes: unaccompanied}
+	}
+
+	var orphans []string
+	for r, row := range layout.Grid {
+		taken := func(c int) bool {
+			if c < 0 || c >= len(row.Cells) || row.Cells[c].Status == models.SeatGap {
+				return false
+			}
+			return selected[[2]int{r, c}] || row.Cells[c].Status != models.SeatAvailable
+		}
+		for c, cell := range row.Cells {
+			if cell.Status != models.SeatAvailable || selected[[2]int{r, c}] || cell.SeatClass != models.SeatClassRegular && cell.SeatClass != models.SeatClassPremium {
+				continue
+			}
+			if taken(c-1) && taken(c+1) && (selected[[2]int{r, c - 1}] || selected[[2]int{r, c + 1}]) {
+				orphans = append(orphans, cell.SeatCode)
+			}
+		}
+	}
+	if len(orphans) > 0 {
+		return &SeatSelectionError{Reason: "orphan_seat", Message: "Selection would leave a single seat empty between booked seats", SeatCodes: orphans}
+	}
+	return nil
 }
 
-func (or *OrderRepo) GetTransactionDetail(ctx context.Context, orderID int) (*models.OrderDetail, error) {
+func selectedWheelchairBeside(layout *models.SeatLayout, selected map[[2]int]bool, r, c int) bool {
+	cells := layout.Grid[r].Cells
+	for _, n := range []int{c - 1, c + 1} {
+		if n >= 0 && n < len(cells) && selected[[2]int{r, n}] && cells[n].SeatClass == models.SeatClassWheelchair {
+			return true
+		}
+	}
+	return false
+}
+
+func (or *OrderRepo) GetTransactionDetail(ctx context.Context, reference string) (*models.OrderDetail, error) {
 	sql := `
-		SELECT o.id, o.qr_code, o.users_id, o.schedules_id, o.payments_id,
-		       o.fullname, o.email, o.phone_number, o.created_at, o.updated_at,
+		SELECT o.id, o.reference,
+		       CASE WHEN o.status IN ('paid', 'checked_in') THEN o.qr_code ELSE '' END,
+		       o.users_id, o.schedules_id, o.payments_id,
+		       o.fullname, o.email, o.phone_number, o.status,
+		       o.subtotal, o.fees, o.discount, o.total, o.created_at, o.updated_at,
 		       m.id, m.backdrop_path, m.overview, m.popularity, m.poster_path,
 		       m.release_date, m.duration, m.title, m.director_name,
 		       c.name as cinema_name, l.name as location, t.time, s.date,
 		       pm.name as payment,
-		       COALESCE(json_agg(json_build_object('id', se.id, 'seat_code', se.seat_code))
+		       COALESCE(json_agg(json_build_object('id', se.id, 'seat_code', se.seat_code, 'seat_class', se.seat_class, 'price', os.price,
+		                                  'released', os.released_at IS NOT NULL))
 		                FILTER (WHERE se.id IS NOT NULL), '[]') as seats
 		FROM orders o
 		JOIN schedules s ON o.schedules_id = s.id
@@ -160,16 +1046,17 @@ func (or *OrderRepo) GetTransactionDetail(ctx context.Context, orderID int) (*mo
 		JOIN payment_methods pm ON o.payments_id = pm.id
 		LEFT JOIN order_seats os ON o.id = os.orders_id
 		LEFT JOIN seats se ON se.id = os.seats_id
-		WHERE o.id = $1
+		WHERE o.reference = $1
 		GROUP BY o.id, m.id, c.name, l.name, t.time, s.date, pm.name
 	`
 
 	var d models.OrderDetail
 	var seatsJSON []byte
 
-	err := or.db.QueryRow(ctx, sql, orderID).Scan(
-		&d.ID, &d.QRCode, &d.UserID, &d.ScheduleID, &d.PaymentID,
-		&d.FullName, &d.Email, &d.Phone, &d.CreatedAt, &d.UpdatedAt,
+	err := or.db.QueryRow(ctx, sql, reference).Scan(
+		&d.ID, &d.Reference, &d.QRCode, &d.UserID, &d.ScheduleID, &d.PaymentID,
+		&d.FullName, &d.Email, &d.Phone, &d.Status,
+		&d.Subtotal, &d.Fees, &d.Discount, &d.Total, &d.CreatedAt, &d.UpdatedAt,
 		&d.Movie.ID, &d.Movie.Backdrop, &d.Movie.Overview, &d.Movie.Popularity,
 		&d.Movie.Poster, &d.Movie.ReleaseDate, &d.Movie.Duration,
 		&d.Movie.Title, &d.Movie.Director,
@@ -178,22 +1065,35 @@ func (or *OrderRepo) GetTransactionDetail(ctx context.Context, orderID int) (*mo
 		&seatsJSON,
 	)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
 		return nil, err
 	}
 
 	_ = json.Unmarshal(seatsJSON, &d.Seats)
+
+	history, err := or.getStatusHistory(ctx, []int{d.ID})
+	if err != nil {
+		return nil, err
+	}
+	d.StatusHistory = history[d.ID]
 	return &d, nil
 }
 
 func (or *OrderRepo) GetOrderHistory(ctx context.Context, userID uuid.UUID) ([]models.OrderDetail, error) {
 	rows, err := or.db.Query(ctx, `
-		SELECT o.id, o.qr_code, o.users_id, o.schedules_id, o.payments_id,
-		       o.fullname, o.email, o.phone_number, o.created_at, o.updated_at,
+		SELECT o.id, o.reference,
+		       CASE WHEN o.status IN ('paid', 'checked_in') THEN o.qr_code ELSE '' END,
+		       o.users_id, o.schedules_id, o.payments_id,
+		       o.fullname, o.email, o.phone_number, o.status,
+		       o.subtotal, o.fees, o.discount, o.total, o.created_at, o.updated_at,
 		       m.id, m.backdrop_path, m.overview, m.popularity, m.poster_path,
 		       m.release_date, m.duration, m.title, m.director_name,
 		       c.name as cinema_name, l.name as location, t.time, s.date,
 		       pm.name as payment,
-		       COALESCE(json_agg(json_build_object('id', se.id, 'seat_code', se.seat_code))
+		       COALESCE(json_agg(json_build_object('id', se.id, 'seat_code', se.seat_code, 'seat_class', se.seat_class, 'price', os.price,
+		                                  'released', os.released_at IS NOT NULL))
 		                FILTER (WHERE se.id IS NOT NULL), '[]') as seats
 		FROM orders o
 		JOIN schedules s ON o.schedules_id = s.id
@@ -219,8 +1119,9 @@ func (or *OrderRepo) GetOrderHistory(ctx context.Context, userID uuid.UUID) ([]m
 		var seatsJSON []byte
 
 		if err := rows.Scan(
-			&d.ID, &d.QRCode, &d.UserID, &d.ScheduleID, &d.PaymentID,
-			&d.FullName, &d.Email, &d.Phone, &d.CreatedAt, &d.UpdatedAt,
+			&d.ID, &d.Reference, &d.QRCode, &d.UserID, &d.ScheduleID, &d.PaymentID,
+			&d.FullName, &d.Email, &d.Phone, &d.Status,
+			&d.Subtotal, &d.Fees, &d.Discount, &d.Total, &d.CreatedAt, &d.UpdatedAt,
 			&d.Movie.ID, &d.Movie.Backdrop, &d.Movie.Overview, &d.Movie.Popularity,
 			&d.Movie.Poster, &d.Movie.ReleaseDate, &d.Movie.Duration,
 			&d.Movie.Title, &d.Movie.Director,
@@ -233,11 +1134,25 @@ func (or *OrderRepo) GetOrderHistory(ctx context.Context, userID uuid.UUID) ([]m
 		_ = json.Unmarshal(seatsJSON, &d.Seats)
 		orders = append(orders, d)
 	}
+
+	orderIDs := make([]int, len(orders))
+	for i, o := range orders {
+		orderIDs[i] = o.ID
+	}
+
+	history, err := or.getStatusHistory(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		orders[i].StatusHistory = history[orders[i].ID]
+	}
 	return orders, nil
 }
 
+// GetPayments lists the payment methods mapped to a provider; the others cannot be paid with.
 func (or *OrderRepo) GetPayments(ctx context.Context) ([]models.PaymentMethod, error) {
-	rows, err := or.db.Query(ctx, `SELECT id, name FROM payment_methods ORDER BY id`)
+	rows, err := or.db.Query(ctx, `SELECT id, name, provider FROM payment_methods WHERE provider IS NOT NULL ORDER BY id`)
 	if err != nil {
 		return nil, err
 	}
@@ -246,7 +1161,7 @@ func (or *OrderRepo) GetPayments(ctx context.Context) ([]models.PaymentMethod, e
 	var payments []models.PaymentMethod
 	for rows.Next() {
 		var p models.PaymentMethod
-		if err := rows.Scan(&p.ID, &p.Name); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.Provider); err != nil {
 			return nil, err
 		}
 		payments = append(payments, p)
@@ -254,6 +1169,15 @@ func (or *OrderRepo) GetPayments(ctx context.Context) ([]models.PaymentMethod, e
 	return payments, nil
 }
 
+func (or *OrderRepo) GetPaymentMethod(ctx context.Context, paymentID int) (*models.PaymentMethod, error) {
+	var p models.PaymentMethod
+	err := or.db.QueryRow(ctx, `SELECT id, name, provider FROM payment_methods WHERE id = $1 AND provider IS NOT NULL`, paymentID).Scan(&p.ID, &p.Name, &p.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
 func (or *OrderRepo) GetCinemas(ctx context.Context) ([]models.Cinema, error) {
 	rows, err := or.db.Query(ctx, `SELECT id, name FROM cinemas ORDER BY id`)
 	if err != nil {
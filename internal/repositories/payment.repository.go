@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/payments"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrPaymentNotFound = errors.New("payment transaction not found")
+
+type PaymentRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPaymentRepo(db *pgxpool.Pool) *PaymentRepo {
+	return &PaymentRepo{db: db}
+}
+
+// GetUnavailableMethods lists the payment methods that cannot be paid with because they
+// have no provider or one that is not in enabled.
+func (pr *PaymentRepo) GetUnavailableMethods(ctx context.Context, enabled []string) ([]models.PaymentMethod, error) {
+	rows, err := pr.db.Query(ctx, `
+		SELECT id, name, COALESCE(provider, '')
+		FROM payment_methods
+		WHERE provider IS NULL OR NOT provider = ANY($1)
+		ORDER BY id
+	`, enabled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var methods []models.PaymentMethod
+	for rows.Next() {
+		var m models.PaymentMethod
+		if err := rows.Scan(&m.ID, &m.Name, &m.Provider); err != nil {
+			return nil, err
+		}
+		methods = append(methods, m)
+	}
+	return methods, rows.Err()
+}
+
+// insertPaymentTransaction records a charge created for an order.
+func insertPaymentTransaction(ctx context.Context, q querier, orderID int, charge *payments.Charge) (*models.PaymentTransaction, error) {
+	t := models.PaymentTransaction{
+		OrderID:     orderID,
+		Provider:    charge.Provider,
+		Reference:   charge.Reference,
+		Amount:      charge.Amount,
+		Status:      string(charge.Status),
+		RedirectURL: &charge.RedirectURL,
+	}
+
+	err := q.QueryRow(ctx, `
+		INSERT INTO payment_transactions (orders_id, provider, reference, amount, status, redirect_url, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,NOW())
+		RETURNING id, created_at
+	`, t.OrderID, t.Provider, t.Reference, t.Amount, t.Status, t.RedirectURL).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ApplyWebhookEvent stores the charge status reported by a provider and marks the
+// order paid when the charge settles. Replayed events are accepted and ignored.
+//
+// Money that arrives for an order that is no longer pending, or that does not match the
+// order total, does not pay the order. It gets a pending refund instead, which is returned
+// for the caller to settle with the provider.
+func (pr *PaymentRepo) ApplyWebhookEvent(ctx context.Context, provider string, event *payments.WebhookEvent) (int, *models.OrderRefund, error) {
+	tx, err := pr.db.Begin(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var transactionID, orderID int
+	var status string
+	err = tx.QueryRow(ctx, `
+		SELECT id, orders_id, status
+		FROM payment_transactions
+		WHERE provider = $1 AND reference = $2
+		FOR UPDATE
+	`, provider, event.Reference).Scan(&transactionID, &orderID, &status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil, ErrPaymentNotFound
+		}
+		return 0, nil, err
+	}
+
+	if status == string(event.Status) {
+		return orderID, nil, tx.Commit(ctx)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE payment_transactions SET status = $1, updated_at = NOW() WHERE id = $2`, event.Status, transactionID); err != nil {
+		return 0, nil, err
+	}
+
+	var refund *models.OrderRefund
+	if event.Status == payments.ChargePaid {
+		var orderStatus models.OrderStatus
+		var total int64
+		var userID uuid.UUID
+		err := tx.QueryRow(ctx, `SELECT status, total, users_id FROM orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&orderStatus, &total, &userID)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch {
+		case event.Amount != total:
+			reason := fmt.Sprintf("Paid %d via %s (%s) for an order total of %d", event.Amount, provider, event.Reference, total)
+			log.Printf("Payment %s for order %d: %s\n", event.Reference, orderID, reason)
+			refund, err = insertUnexpectedPaymentRefund(ctx, tx, orderID, userID, provider, event, reason)
+		case orderStatus != models.OrderStatusPending:
+			reason := fmt.Sprintf("Paid via %s (%s) after the order was %s", provider, event.Reference, orderStatus)
+			log.Printf("Payment %s for order %d: %s\n", event.Reference, orderID, reason)
+			refund, err = insertUnexpectedPaymentRefund(ctx, tx, orderID, userID, provider, event, reason)
+		default:
+			note := fmt.Sprintf("Paid via %s (%s)", provider, event.Reference)
+			err = transitionStatus(ctx, tx, orderID, orderStatus, models.OrderStatusPaid, note)
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	return orderID, refund, tx.Commit(ctx)
+}
+
+// insertUnexpectedPaymentRefund queues the return of a payment the order cannot accept.
+// It is booked on the order's owner since nobody asked for it.
+func insertUnexpectedPaymentRefund(ctx context.Context, tx pgx.Tx, orderID int, userID uuid.UUID, provider string, event *payments.WebhookEvent, reason string) (*models.OrderRefund, error) {
+	r := models.OrderRefund{
+		OrderID:         orderID,
+		Provider:        &provider,
+		ChargeReference: &event.Reference,
+		Amount:          event.Amount,
+		SeatCodes:       []string{},
+		Reason:          &reason,
+		Status:          models.RefundPending,
+		RequestedBy:     userID,
+	}
+	if r.Amount <= 0 {
+		r.Status = models.RefundManual
+	}
+
+	err := tx.QueryRow(ctx, `
+		INSERT INTO order_refunds (orders_id, provider, charge_reference, amount, seat_codes, reason, status, requested_by, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,NOW())
+		RETURNING id, created_at
+	`, r.OrderID, r.Provider, r.ChargeReference, r.Amount, r.SeatCodes, r.Reason, r.Status, r.RequestedBy).Scan(&r.ID, &r.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// CompleteRefund records the provider's answer to a pending refund.
+func (pr *PaymentRepo) CompleteRefund(ctx context.Context, refund *models.OrderRefund) error {
+	err := pr.db.QueryRow(ctx, `
+		UPDATE order_refunds SET reference = $2, status = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, refund.ID, refund.Reference, refund.Status).Scan(&refund.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if refund.Status != models.RefundRefunded {
+		return nil
+	}
+
+	_, err = pr.db.Exec(ctx, `
+		UPDATE payment_transactions pt SET status = 'refunded', updated_at = NOW()
+		WHERE pt.provider = $1 AND pt.reference = $2 AND pt.amount <= (
+			SELECT COALESCE(SUM(r.amount), 0) FROM order_refunds r
+			WHERE r.provider = pt.provider AND r.charge_reference = pt.reference AND r.status = $3
+		)
+	`, refund.Provider, refund.ChargeReference, models.RefundRefunded)
+	return err
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/payments"
+)
+
+func TestApplyWebhookEventPaysPendingOrder(t *testing.T) {
+	db := testDB(t)
+	f := newOrderFixture(t, db)
+	ctx := context.Background()
+
+	orders := NewOrderRepo(db, nil)
+	order, charge, err := orders.CreateOrder(ctx, f.order(), f.seatIDs[:1], &testProvider{})
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	if order.QRCode != "" {
+		t.Fatal("unpaid order got a ticket")
+	}
+
+	event := &payments.WebhookEvent{Reference: charge.Reference, Status: payments.ChargePaid, Amount: order.Total}
+	_, refund, err := NewPaymentRepo(db).ApplyWebhookEvent(ctx, "test", event)
+	if err != nil || refund != nil {
+		t.Fatalf("ApplyWebhookEvent: refund %v, %v; want none", refund, err)
+	}
+	detail, err := orders.GetTransactionDetail(ctx, order.Reference)
+	if err != nil {
+		t.Fatalf("GetTransactionDetail: %v", err)
+	}
+	if detail.Status != models.OrderStatusPaid || detail.QRCode == "" {
+		t.Fatalf("order %s with ticket %q, want a paid order with a ticket", detail.Status, detail.QRCode)
+	}
+}
+
+func TestApplyWebhookEventRefundsUnacceptedPayments(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		expire bool
+		amount func(total int64) int64
+	}{
+		{"expired order", true, func(total int64) int64 { return total }},
+		{"amount mismatch", false, func(total int64) int64 { return total - 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOrderFixture(t, db)
+			order, charge, err := NewOrderRepo(db, nil).CreateOrder(ctx, f.order(), f.seatIDs[:1], &testProvider{})
+			if err != nil {
+				t.Fatalf("CreateOrder: %v", err)
+			}
+			want := models.OrderStatusPending
+			if tt.expire {
+				want = models.OrderStatusExpired
+				if _, err := db.Exec(ctx, `UPDATE orders SET status = $2 WHERE id = $1`, order.ID, want); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			event := &payments.WebhookEvent{Reference: charge.Reference, Status: payments.ChargePaid, Amount: tt.amount(order.Total)}
+			_, refund, err := NewPaymentRepo(db).ApplyWebhookEvent(ctx, "test", event)
+			if err != nil {
+				t.Fatalf("ApplyWebhookEvent: %v", err)
+			}
+
+			if refund == nil || refund.Status != models.RefundPending || refund.Amount != event.Amount {
+				t.Fatalf("refund %+v, want a pending refund of %d", refund, event.Amount)
+			}
+			if status := orderStatus(t, db, order.ID); status != want {
+				t.Fatalf("order %s, want %s", status, want)
+			}
+		})
+	}
+}
+
+func orderStatus(t *testing.T, db querier, orderID int) models.OrderStatus {
+	t.Helper()
+	var status models.OrderStatus
+	if err := db.QueryRow(context.Background(), `SELECT status FROM orders WHERE id = $1`, orderID).Scan(&status); err != nil {
+		t.Fatal(err)
+	}
+	return status
+}
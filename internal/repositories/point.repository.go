@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrInsufficientPoints = errors.New("insufficient points")
+
+// pointRules decide how many points a paid order earns and what a point is worth when
+// redeemed. They are read from the environment on use.
+type pointRules struct {
+	perTicket         int
+	amountUnit        int64
+	weekdayMultiplier float64
+	value             int64
+}
+
+func loadPointRules() pointRules {
+	return pointRules{
+		perTicket:         utils.GetEnvInt("POINTS_PER_TICKET", 10),
+		amountUnit:        int64(max(utils.GetEnvInt("POINTS_AMOUNT_UNIT", 10000), 1)),
+		weekdayMultiplier: utils.GetEnvFloat("POINTS_WEEKDAY_MULTIPLIER", 1.5),
+		value:             int64(max(utils.GetEnvInt("POINTS_VALUE", 100), 1)),
+	}
+}
+
+// earned is the points for tickets seats bought for amount, for a show on date.
+func (r pointRules) earned(tickets int, amount int64, date time.Time) int {
+	points := float64(tickets*r.perTicket) + float64(amount/r.amountUnit)
+	if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+		points *= r.weekdayMultiplier
+	}
+	return int(math.Floor(points))
+}
+
+type PointRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPointRepo(db *pgxpool.Pool) *PointRepo {
+	return &PointRepo{db: db}
+}
+
+// GetPointHistory returns the user's balance and ledger, newest entries first.
+func (pr *PointRepo) GetPointHistory(ctx context.Context, userID uuid.UUID) (*models.PointHistory, error) {
+	history := models.PointHistory{Entries: []models.PointEntry{}}
+	if err := pr.db.QueryRow(ctx, `SELECT point FROM profile WHERE user_id = $1`, userID).Scan(&history.Balance); err != nil {
+		return nil, err
+	}
+
+	rows, err := pr.db.Query(ctx, `
+		SELECT pl.id, pl.orders_id, o.reference, pl.kind, pl.points, pl.balance, pl.note, pl.created_at
+		FROM point_ledger pl
+		LEFT JOIN orders o ON o.id = pl.orders_id
+		WHERE pl.users_id = $1
+		ORDER BY pl.created_at DESC, pl.id DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e := models.PointEntry{UserID: userID}
+		if err := rows.Scan(&e.ID, &e.OrderID, &e.Reference, &e.Kind, &e.Points, &e.Balance, &e.Note, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		history.Entries = append(history.Entries, e)
+	}
+	return &history, rows.Err()
+}
+
+// addPoints changes a user's balance and records it in the ledger. A negative change
+// fails with ErrInsufficientPoints when the balance would drop below zero, unless it
+// reverses points already given, which may leave the balance negative.
+func addPoints(ctx context.Context, tx pgx.Tx, userID uuid.UUID, orderID int, kind models.PointKind, points int, note string) error {
+	if points == 0 {
+		return nil
+	}
+
+	var balance int
+	err := tx.QueryRow(ctx, `
+		UPDATE profile SET point = point + $2
+		WHERE user_id = $1 AND ($3 OR point + $2 >= 0)
+		RETURNING point
+	`, userID, points, kind == models.PointReverse).Scan(&balance)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInsufficientPoints
+		}
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO point_ledger (users_id, orders_id, kind, points, balance, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+	`, userID, orderID, kind, points, balance, note)
+	return err
+}
+
+// accruePoints credits the points a paid order earns. An order earns once.
+func accruePoints(ctx context.Context, tx pgx.Tx, orderID int) error {
+	var (
+		userID  uuid.UUID
+		total   int64
+		earned  int
+		date    time.Time
+		tickets int
+	)
+	err := tx.QueryRow(ctx, `
+		SELECT o.users_id, o.total, o.points_earned, s.date,
+		       (SELECT COUNT(*) FROM order_seats os WHERE os.orders_id = o.id AND os.released_at IS NULL)
+		FROM orders o
+		JOIN schedules s ON s.id = o.schedules_id
+		WHERE o.id = $1
+	`, orderID).Scan(&userID, &total, &earned, &date, &tickets)
+	if err != nil {
+		return err
+	}
+	if earned > 0 {
+		return nil
+	}
+
+	points := loadPointRules().earned(tickets, total, date)
+	if points <= 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, `UPDATE orders SET points_earned = $2 WHERE id = $1`, orderID, points); err != nil {
+		return err
+	}
+	return addPoints(ctx, tx, userID, orderID, models.PointEarn, points, "Earned on payment")
+}
+
+// reversePoints takes back the points earned for cancelled seats, their share of all the
+// seats the order was bought with. With cancelled zero everything not yet reversed is
+// taken back.
+func reversePoints(ctx context.Context, tx pgx.Tx, orderID, cancelled int, note string) error {
+	var userID uuid.UUID
+	var earned, reversed, seats int
+	err := tx.QueryRow(ctx, `
+		SELECT o.users_id, o.points_earned,
+		       COALESCE((SELECT -SUM(points) FROM point_ledger WHERE orders_id = o.id AND kind = $2), 0),
+		       (SELECT COUNT(*) FROM order_seats WHERE orders_id = o.id)
+		FROM orders o
+		WHERE o.id = $1
+	`, orderID, models.PointReverse).Scan(&userID, &earned, &reversed, &seats)
+	if err != nil {
+		return err
+	}
+
+	points := earned - reversed
+	if cancelled > 0 && cancelled < seats {
+		points = min(earned*cancelled/seats, points)
+	}
+	if points <= 0 {
+		return nil
+	}
+	return addPoints(ctx, tx, userID, orderID, models.PointReverse, -points, note)
+}
+
+// restorePoints gives back the points redeemed on an order that was called off as a whole.
+func restorePoints(ctx context.Context, tx pgx.Tx, orderID int, note string) error {
+	var userID uuid.UUID
+	var redeemed int
+	var restored bool
+	err := tx.QueryRow(ctx, `
+		SELECT o.users_id, o.points_redeemed,
+		       EXISTS(SELECT 1 FROM point_ledger WHERE orders_id = o.id AND kind = $2)
+		FROM orders o
+		WHERE o.id = $1
+	`, orderID, models.PointRestore).Scan(&userID, &redeemed, &restored)
+	if err != nil {
+		return err
+	}
+	if restored || redeemed <= 0 {
+		return nil
+	}
+	return addPoints(ctx, tx, userID, orderID, models.PointRestore, redeemed, note)
+}
@@ -0,0 +1,235 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrPromoNotFound = errors.New("promo code not found")
+	ErrPromoExists   = errors.New("promo code already exists")
+)
+
+// PromoRejectedError explains why a promo code cannot be used on an order. Reason is a
+// stable machine readable code, Message is meant for the customer.
+type PromoRejectedError struct {
+	Reason  string
+	Message string
+}
+
+func (e *PromoRejectedError) Error() string {
+	return e.Reason + ": " + e.Message
+}
+
+// promoOrder is what a promo code is checked against.
+type promoOrder struct {
+	userID     uuid.UUID
+	scheduleID int
+	paymentID  int
+	tickets    int
+}
+
+const promoColumns = `id, code, description, discount_type, discount_value, max_discount, min_tickets,
+	starts_at, ends_at, movie_ids, cinema_ids, payment_ids, usage_limit, per_user_limit, used_count,
+	created_at, updated_at`
+
+func scanPromo(row pgx.Row) (*models.PromoCode, error) {
+	var p models.PromoCode
+	err := row.Scan(&p.ID, &p.Code, &p.Description, &p.DiscountType, &p.DiscountValue, &p.MaxDiscount, &p.MinTickets,
+		&p.StartsAt, &p.EndsAt, &p.MovieIDs, &p.CinemaIDs, &p.PaymentIDs, &p.UsageLimit, &p.PerUserLimit, &p.UsedCount,
+		&p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPromoNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// findPromo looks a live promo code up by its code. Inside a checkout the row is locked,
+// so concurrent checkouts with the same code count their usage one after the other.
+func findPromo(ctx context.Context, q querier, code string, lock bool) (*models.PromoCode, error) {
+	query := `SELECT ` + promoColumns + ` FROM promo_codes WHERE code = $1 AND deleted_at IS NULL`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	return scanPromo(q.QueryRow(ctx, query, normalizePromoCode(code)))
+}
+
+// checkPromo verifies every restriction of the promo code against the order.
+func checkPromo(ctx context.Context, q querier, promo *models.PromoCode, order promoOrder) error {
+	var movieID, cinemaID, used int
+	var now time.Time
+	err := q.QueryRow(ctx, `
+		SELECT s.movies_id, s.cinemas_id, LOCALTIMESTAMP,
+		       (SELECT COUNT(*) FROM promo_redemptions
+		        WHERE promo_codes_id = $2 AND users_id = $3 AND released_at IS NULL)
+		FROM schedules s
+		WHERE s.id = $1
+	`, order.scheduleID, promo.ID, order.userID).Scan(&movieID, &cinemaID, &now, &used)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrScheduleNotFound
+		}
+		return err
+	}
+
+	switch {
+	case promo.StartsAt != nil && now.Before(*promo.StartsAt):
+		return &PromoRejectedError{Reason: "not_started", Message: "Promo code is valid from " + promo.StartsAt.Format("2006-01-02 15:04")}
+	case promo.EndsAt != nil && now.After(*promo.EndsAt):
+		return &PromoRejectedError{Reason: "ended", Message: "Promo code has ended"}
+	case order.tickets < promo.MinTickets:
+		return &PromoRejectedError{Reason: "min_tickets", Message: fmt.Sprintf("Promo code needs at least %d tickets", promo.MinTickets)}
+	case len(promo.MovieIDs) > 0 && !slices.Contains(promo.MovieIDs, movieID):
+		return &PromoRejectedError{Reason: "movie", Message: "Promo code is not valid for this movie"}
+	case len(promo.CinemaIDs) > 0 && !slices.Contains(promo.CinemaIDs, cinemaID):
+		return &PromoRejectedError{Reason: "cinema", Message: "Promo code is not valid at this cinema"}
+	case order.paymentID != 0 && len(promo.PaymentIDs) > 0 && !slices.Contains(promo.PaymentIDs, order.paymentID):
+		return &PromoRejectedError{Reason: "payment_method", Message: "Promo code is not valid for this payment method"}
+	case promo.UsageLimit != nil && promo.UsedCount >= *promo.UsageLimit:
+		return &PromoRejectedError{Reason: "usage_limit", Message: "Promo code has been fully used"}
+	case promo.PerUserLimit != nil && used >= *promo.PerUserLimit:
+		return &PromoRejectedError{Reason: "user_limit", Message: "You have already used this promo code"}
+	}
+	return nil
+}
+
+// redeemPromo counts one use of the promo code for the order. The guarded update keeps
+// the global limit even if the promo row was not locked beforehand.
+func redeemPromo(ctx context.Context, tx pgx.Tx, promo *models.PromoCode, orderID int, userID uuid.UUID, discount int64) error {
+	tag, err := tx.Exec(ctx, `
+		UPDATE promo_codes SET used_count = used_count + 1
+		WHERE id = $1 AND (usage_limit IS NULL OR used_count < usage_limit)
+	`, promo.ID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return &PromoRejectedError{Reason: "usage_limit", Message: "Promo code has been fully used"}
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO promo_redemptions (promo_codes_id, orders_id, users_id, discount, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+	`, promo.ID, orderID, userID, discount)
+	return err
+}
+
+// releasePromo gives the use of a promo code back when its order is called off as a whole.
+func releasePromo(ctx context.Context, tx pgx.Tx, orderID int) error {
+	var promoID int
+	err := tx.QueryRow(ctx, `
+		UPDATE promo_redemptions SET released_at = NOW()
+		WHERE orders_id = $1 AND released_at IS NULL
+		RETURNING promo_codes_id
+	`, orderID).Scan(&promoID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE promo_codes SET used_count = GREATEST(used_count - 1, 0) WHERE id = $1`, promoID)
+	return err
+}
+
+type PromoRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPromoRepo(db *pgxpool.Pool) *PromoRepo {
+	return &PromoRepo{db: db}
+}
+
+func (pr *PromoRepo) GetPromos(ctx context.Context) ([]models.PromoCode, error) {
+	rows, err := pr.db.Query(ctx, `SELECT `+promoColumns+` FROM promo_codes WHERE deleted_at IS NULL ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promos := []models.PromoCode{}
+	for rows.Next() {
+		p, err := scanPromo(rows)
+		if err != nil {
+			return nil, err
+		}
+		promos = append(promos, *p)
+	}
+	return promos, rows.Err()
+}
+
+func (pr *PromoRepo) GetPromoByID(ctx context.Context, id int) (*models.PromoCode, error) {
+	return scanPromo(pr.db.QueryRow(ctx, `SELECT `+promoColumns+` FROM promo_codes WHERE id = $1 AND deleted_at IS NULL`, id))
+}
+
+func (pr *PromoRepo) CreatePromo(ctx context.Context, p *models.PromoCode) (*models.PromoCode, error) {
+	row := pr.db.QueryRow(ctx, `
+		INSERT INTO promo_codes (code, description, discount_type, discount_value, max_discount, min_tickets,
+		                         starts_at, ends_at, movie_ids, cinema_ids, payment_ids, usage_limit, per_user_limit, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW())
+		RETURNING `+promoColumns,
+		normalizePromoCode(p.Code), p.Description, p.DiscountType, p.DiscountValue, p.MaxDiscount, p.MinTickets,
+		p.StartsAt, p.EndsAt, promoIDs(p.MovieIDs), promoIDs(p.CinemaIDs), promoIDs(p.PaymentIDs), p.UsageLimit, p.PerUserLimit,
+	)
+	created, err := scanPromo(row)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrPromoExists
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdatePromo replaces the editable fields of a promo code. The code itself and its
+// usage count stay as they are.
+func (pr *PromoRepo) UpdatePromo(ctx context.Context, p *models.PromoCode) (*models.PromoCode, error) {
+	return scanPromo(pr.db.QueryRow(ctx, `
+		UPDATE promo_codes SET description = $2, discount_type = $3, discount_value = $4, max_discount = $5,
+		       min_tickets = $6, starts_at = $7, ends_at = $8, movie_ids = $9, cinema_ids = $10, payment_ids = $11,
+		       usage_limit = $12, per_user_limit = $13, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING `+promoColumns,
+		p.ID, p.Description, p.DiscountType, p.DiscountValue, p.MaxDiscount,
+		p.MinTickets, p.StartsAt, p.EndsAt, promoIDs(p.MovieIDs), promoIDs(p.CinemaIDs), promoIDs(p.PaymentIDs),
+		p.UsageLimit, p.PerUserLimit,
+	))
+}
+
+func (pr *PromoRepo) DeletePromo(ctx context.Context, id int) error {
+	tag, err := pr.db.Exec(ctx, `UPDATE promo_codes SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPromoNotFound
+	}
+	return nil
+}
+
+// promoIDs keeps a missing restriction list an empty array instead of NULL.
+func promoIDs(ids []int) []int {
+	if ids == nil {
+		return []int{}
+	}
+	return ids
+}
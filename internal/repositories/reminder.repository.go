@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// A reminder still marked sending after this long was abandoned by a crashed worker.
+const reminderSendingTimeout = 10 * time.Minute
+
+const reminderColumns = `r.id, r.orders_id, o.reference, r.channel, r.status, r.attempts, r.last_error,
+	r.send_at, r.sent_at, r.created_at, r.updated_at`
+
+// scheduleReminders queues a reminder on every channel the owner has enabled for each
+// upcoming paid order matched by filter. Pending and cancelled reminders are moved to
+// the current preferences; sent or failed ones are left alone. Showtimes are compared
+// with $8, the time at the cinemas, and send_at is in that zone too.
+const scheduleReminders = `
+	INSERT INTO reminders (orders_id, channel, status, send_at, created_at)
+	SELECT o.id, ch.channel, $2,
+	       GREATEST(s.date + t.time::time - make_interval(hours => p.reminder_hours), $8), NOW()
+	FROM orders o
+	JOIN schedules s ON s.id = o.schedules_id
+	JOIN times t ON t.id = s.times_id
+	JOIN profile p ON p.user_id = o.users_id
+	CROSS JOIN LATERAL (VALUES ($3, p.notify_email), ($4, p.notify_push), ($5, p.notify_sms)) AS ch(channel, enabled)
+	WHERE %s AND o.status = $6 AND ch.enabled AND s.date + t.time::time > $8
+	ON CONFLICT (orders_id, channel) DO UPDATE
+	SET status = EXCLUDED.status, send_at = EXCLUDED.send_at, attempts = 0, last_error = NULL, updated_at = NOW()
+	WHERE reminders.status IN ($2, $7)
+`
+
+type ReminderRepo struct {
+	db *pgxpool.Pool
+}
+
+// cinemaNow is the time at the cinemas, the zone schedule dates and times are written
+// in, so showtimes compare right whatever time zone the database runs in.
+func cinemaNow() time.Time {
+	return time.Now().In(utils.CinemaLocation())
+}
+
+func NewReminderRepo(db *pgxpool.Pool) *ReminderRepo {
+	return &ReminderRepo{db: db}
+}
+
+func execScheduleReminders(ctx context.Context, tx pgx.Tx, filter string, arg any) error {
+	_, err := tx.Exec(ctx, fmt.Sprintf(scheduleReminders, filter), arg, models.ReminderPending,
+		models.ChannelEmail, models.ChannelPush, models.ChannelSMS, models.OrderStatusPaid, models.ReminderCancelled, cinemaNow())
+	return err
+}
+
+// queueReminders schedules the showtime reminders of an order that just got paid.
+func queueReminders(ctx context.Context, tx pgx.Tx, orderID int) error {
+	return execScheduleReminders(ctx, tx, "o.id = $1", orderID)
+}
+
+// syncReminders brings the pending reminders of a user's orders in line with their
+// notification preferences, which the caller has just updated in tx.
+func syncReminders(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE reminders r SET status = $2, updated_at = NOW()
+		FROM orders o, profile p
+		WHERE o.id = r.orders_id AND p.user_id = o.users_id AND o.users_id = $1 AND r.status = $3
+		  AND NOT CASE r.channel WHEN $4 THEN p.notify_email WHEN $5 THEN p.notify_push WHEN $6 THEN p.notify_sms ELSE false END
+	`, userID, models.ReminderCancelled, models.ReminderPending, models.ChannelEmail, models.ChannelPush, models.ChannelSMS)
+	if err != nil {
+		return err
+	}
+	return execScheduleReminders(ctx, tx, "o.users_id = $1", userID)
+}
+
+// cancelReminders drops the reminders of an order that will not be attended.
+func cancelReminders(ctx context.Context, tx pgx.Tx, orderID int) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE reminders SET status = $2, updated_at = NOW()
+		WHERE orders_id = $1 AND status IN ($3, $4)
+	`, orderID, models.ReminderCancelled, models.ReminderPending, models.ReminderSending)
+	return err
+}
+
+func scanReminders(rows pgx.Rows) ([]models.Reminder, error) {
+	defer rows.Close()
+
+	reminders := []models.Reminder{}
+	for rows.Next() {
+		var r models.Reminder
+		err := rows.Scan(&r.ID, &r.OrderID, &r.Reference, &r.Channel, &r.Status, &r.Attempts, &r.LastError,
+			&r.SendAt, &r.SentAt, &r.CreatedAt, &r.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, r)
+	}
+	return reminders, rows.Err()
+}
+
+// ClaimDue marks up to limit due reminders as sending and counts the attempt.
+func (rr *ReminderRepo) ClaimDue(ctx context.Context, limit int) ([]models.Reminder, error) {
+	rows, err := rr.db.Query(ctx, `
+		WITH due AS (
+			SELECT id FROM reminders
+			WHERE (status = $1 AND send_at <= $5)
+			   OR (status = $2 AND updated_at < NOW() - make_interval(secs => $3))
+			ORDER BY send_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE reminders r SET status = $2, attempts = r.attempts + 1, updated_at = NOW()
+			FROM due
+			WHERE r.id = due.id
+			RETURNING r.*
+		)
+		SELECT `+reminderColumns+`
+		FROM claimed r
+		JOIN orders o ON o.id = r.orders_id
+		ORDER BY r.send_at
+	`, models.ReminderPending, models.ReminderSending, reminderSendingTimeout.Seconds(), limit, cinemaNow())
+	if err != nil {
+		return nil, err
+	}
+	return scanReminders(rows)
+}
+
+func (rr *ReminderRepo) MarkSent(ctx context.Context, id int) error {
+	_, err := rr.db.Exec(ctx, `
+		UPDATE reminders SET status = $2, last_error = NULL, sent_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, id, models.ReminderSent)
+	return err
+}
+
+// MarkCancelled drops a claimed reminder that is no longer wanted, noting why.
+func (rr *ReminderRepo) MarkCancelled(ctx context.Context, id int, reason string) error {
+	_, err := rr.db.Exec(ctx, `
+		UPDATE reminders SET status = $2, last_error = $3, updated_at = NOW()
+		WHERE id = $1
+	`, id, models.ReminderCancelled, reason)
+	return err
+}
+
+// MarkFailed records a failed attempt. With retryAt set the reminder is tried again
+// then, otherwise it is given up on.
+func (rr *ReminderRepo) MarkFailed(ctx context.Context, id int, cause string, retryAt *time.Time) error {
+	if retryAt == nil {
+		_, err := rr.db.Exec(ctx, `
+			UPDATE reminders SET status = $2, last_error = $3, updated_at = NOW()
+			WHERE id = $1
+		`, id, models.ReminderFailed, cause)
+		return err
+	}
+
+	_, err := rr.db.Exec(ctx, `
+		UPDATE reminders SET status = $2, last_error = $3, send_at = $4, updated_at = NOW()
+		WHERE id = $1
+	`, id, models.ReminderPending, cause, retryAt.In(utils.CinemaLocation()))
+	return err
+}
+
+func (rr *ReminderRepo) GetOrderReminders(ctx context.Context, orderID int) ([]models.Reminder, error) {
+	rows, err := rr.db.Query(ctx, `
+		SELECT `+reminderColumns+`
+		FROM reminders r
+		JOIN orders o ON o.id = r.orders_id
+		WHERE r.orders_id = $1
+		ORDER BY r.channel
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return scanReminders(rows)
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// acquire all seats or none: returns the 1-based indexes of keys held by someone else
+var holdSeatsScript = redis.NewScript(`
+local taken = {}
+for i, key in ipairs(KEYS) do
+	local owner = redis.call('GET', key)
+	if owner and owner ~= ARGV[1] then
+		table.insert(taken, i)
+	end
+end
+if #taken > 0 then
+	return taken
+end
+for _, key in ipairs(KEYS) do
+	redis.call('SET', key, ARGV[1], 'PX', ARGV[2])
+end
+return taken
+`)
+
+// extend all seats or none: returns the 1-based indexes of keys not held by the owner
+var extendSeatsScript = redis.NewScript(`
+local missing = {}
+for i, key in ipairs(KEYS) do
+	if redis.call('GET', key) ~= ARGV[1] then
+		table.insert(missing, i)
+	end
+end
+if #missing > 0 then
+	return missing
+end
+for _, key in ipairs(KEYS) do
+	redis.call('PEXPIRE', key, ARGV[2])
+end
+return missing
+`)
+
+var releaseSeatsScript = redis.NewScript(`
+local released = 0
+for _, key in ipairs(KEYS) do
+	if redis.call('GET', key) == ARGV[1] then
+		released = released + redis.call('DEL', key)
+	end
+end
+return released
+`)
+
+type SeatHoldRepo struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+func NewSeatHoldRepo(rdb *redis.Client) *SeatHoldRepo {
+	return &SeatHoldRepo{
+		rdb: rdb,
+		ttl: utils.GetEnvDuration("SEAT_HOLD_TTL", 10*time.Minute),
+	}
+}
+
+// hash tag keeps every seat of a schedule in the same cluster slot for the scripts above
+func seatHoldKey(scheduleID int, seatCode string) string {
+	return fmt.Sprintf("seathold:{%d}:%s", scheduleID, seatCode)
+}
+
+func seatHoldKeys(scheduleID int, seatCodes []string) []string {
+	keys := make([]string, len(seatCodes))
+	for i, code := range seatCodes {
+		keys[i] = seatHoldKey(scheduleID, code)
+	}
+	return keys
+}
+
+func pickSeatCodes(seatCodes []string, indexes []int64) []string {
+	codes := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		codes = append(codes, seatCodes[i-1])
+	}
+	return codes
+}
+
+// Hold locks every seat for the owner, or none of them. The second return value lists
+// the seats currently held by another user when the hold could not be placed.
+func (sr *SeatHoldRepo) Hold(ctx context.Context, scheduleID int, seatCodes []string, owner uuid.UUID) (*models.SeatHold, []string, error) {
+	taken, err := holdSeatsScript.Run(ctx, sr.rdb, seatHoldKeys(scheduleID, seatCodes), owner.String(), sr.ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(taken) > 0 {
+		return nil, pickSeatCodes(seatCodes, taken), nil
+	}
+
+	return &models.SeatHold{
+		ScheduleID: scheduleID,
+		SeatCodes:  seatCodes,
+		ExpiresAt:  time.Now().Add(sr.ttl),
+	}, nil, nil
+}
+
+// Extend refreshes the TTL of a hold. The second return value lists the seats the owner
+// no longer holds, in which case nothing is extended.
+func (sr *SeatHoldRepo) Extend(ctx context.Context, scheduleID int, seatCodes []string, owner uuid.UUID) (*models.SeatHold, []string, error) {
+	missing, err := extendSeatsScript.Run(ctx, sr.rdb, seatHoldKeys(scheduleID, seatCodes), owner.String(), sr.ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(missing) > 0 {
+		return nil, pickSeatCodes(seatCodes, missing), nil
+	}
+
+	return &models.SeatHold{
+		ScheduleID: scheduleID,
+		SeatCodes:  seatCodes,
+		ExpiresAt:  time.Now().Add(sr.ttl),
+	}, nil, nil
+}
+
+func (sr *SeatHoldRepo) Release(ctx context.Context, scheduleID int, seatCodes []string, owner uuid.UUID) error {
+	return releaseSeatsScript.Run(ctx, sr.rdb, seatHoldKeys(scheduleID, seatCodes), owner.String()).Err()
+}
+
+// NotHeldBy returns the seats that are not currently held by the owner.
+func (sr *SeatHoldRepo) NotHeldBy(ctx context.Context, scheduleID int, seatCodes []string, owner uuid.UUID) ([]string, error) {
+	holders, err := sr.Holders(ctx, scheduleID, seatCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, code := range seatCodes {
+		if holders[code] != owner.String() {
+			missing = append(missing, code)
+		}
+	}
+	return missing, nil
+}
+
+// Holders maps every held seat code to the id of the user holding it.
+func (sr *SeatHoldRepo) Holders(ctx context.Context, scheduleID int, seatCodes []string) (map[string]string, error) {
+	holders := map[string]string{}
+	if len(seatCodes) == 0 {
+		return holders, nil
+	}
+
+	values, err := sr.rdb.MGet(ctx, seatHoldKeys(scheduleID, seatCodes)...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range values {
+		if owner, ok := v.(string); ok {
+			holders[seatCodes[i]] = owner
+		}
+	}
+	return holders, nil
+}
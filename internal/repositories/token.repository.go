@@ -0,0 +1,379 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/Darari17/be-tickitz-full/pkg"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	// ErrRefreshTokenReused means a refresh token was presented after it had been
+	// rotated, so it was probably stolen. Its whole family has been revoked.
+	ErrRefreshTokenReused = errors.New("refresh token was already used")
+	ErrUserTokenInvalid   = errors.New("token is invalid, expired or already used")
+)
+
+// RefreshTokenRepo stores rotating refresh tokens, keeping only their SHA-256. Tokens
+// descending from the same login share a family, which is revoked as a whole on logout
+// or when an already rotated token shows up again.
+type RefreshTokenRepo struct {
+	db  *pgxpool.Pool
+	ttl time.Duration
+}
+
+func NewRefreshTokenRepo(db *pgxpool.Pool) *RefreshTokenRepo {
+	return &RefreshTokenRepo{
+		db:  db,
+		ttl: utils.GetEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (rr *RefreshTokenRepo) insert(ctx context.Context, q querier, userID, familyID uuid.UUID) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	var id int
+	err := q.QueryRow(ctx, `
+		INSERT INTO refresh_tokens (users_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, LOCALTIMESTAMP + make_interval(secs => $4), NOW())
+		RETURNING id
+	`, userID, familyID, hashToken(token), rr.ttl.Seconds()).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Issue starts a new token family for a fresh login.
+func (rr *RefreshTokenRepo) Issue(ctx context.Context, userID uuid.UUID) (string, error) {
+	return rr.insert(ctx, rr.db, userID, uuid.New())
+}
+
+// Rotate exchanges a refresh token for a new one in the same family and returns the
+// user it belongs to.
+func (rr *RefreshTokenRepo) Rotate(ctx context.Context, token string) (*models.User, string, error) {
+	tx, err := rr.db.Begin(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		tokenID  int
+		familyID uuid.UUID
+		expired  bool
+		used     bool
+		revoked  bool
+		user     models.User
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT rt.id, rt.family_id, rt.expires_at <= LOCALTIMESTAMP, rt.used_at IS NOT NULL, rt.revoked_at IS NOT NULL,
+		       u.id, u.email, u.role
+		FROM refresh_tokens rt
+		JOIN users u ON u.id = rt.users_id
+		WHERE rt.token_hash = $1
+		FOR UPDATE OF rt
+	`, hashToken(token)).Scan(&tokenID, &familyID, &expired, &used, &revoked, &user.ID, &user.Email, &user.Role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", ErrRefreshTokenInvalid
+		}
+		return nil, "", err
+	}
+
+	if revoked || expired {
+		return nil, "", ErrRefreshTokenInvalid
+	}
+	if used {
+		if err := revokeFamily(ctx, tx, familyID); err != nil {
+			return nil, "", err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, "", err
+		}
+		return nil, "", ErrRefreshTokenReused
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1`, tokenID); err != nil {
+		return nil, "", err
+	}
+	next, err := rr.insert(ctx, tx, user.ID, familyID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, "", err
+	}
+	return &user, next, nil
+}
+
+// Revoke ends the session a refresh token belongs to. Tokens of other users are
+// ignored so a leaked token cannot be used to log someone else out.
+func (rr *RefreshTokenRepo) Revoke(ctx context.Context, userID uuid.UUID, token string) error {
+	_, err := rr.db.Exec(ctx, `
+		UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE revoked_at IS NULL AND family_id = (
+			SELECT family_id FROM refresh_tokens WHERE token_hash = $1 AND users_id = $2
+		)
+	`, hashToken(token), userID)
+	return err
+}
+
+func revokeFamily(ctx context.Context, tx pgx.Tx, familyID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`, familyID)
+	return err
+}
+
+// revokeAllRefreshTokens ends every session of the user.
+func revokeAllRefreshTokens(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE users_id = $1 AND revoked_at IS NULL`, userID)
+	return err
+}
+
+// UserTokenRepo stores the single-use tokens mailed to users, keeping only their SHA-256.
+type UserTokenRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewUserTokenRepo(db *pgxpool.Pool) *UserTokenRepo {
+	return &UserTokenRepo{db: db}
+}
+
+const insertUserToken = `
+	INSERT INTO user_tokens (users_id, purpose, token_hash, expires_at, created_at)
+	VALUES ($1, $2, $3, LOCALTIMESTAMP + make_interval(secs => $4), NOW())
+`
+
+// Issue creates a token for purpose that can be used once within ttl.
+func (ut *UserTokenRepo) Issue(ctx context.Context, userID uuid.UUID, purpose models.UserTokenPurpose, ttl time.Duration) (string, error) {
+	token, err := pkg.NewUserToken(string(purpose))
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := ut.db.Exec(ctx, insertUserToken, userID, purpose, hashToken(token), ttl.Seconds()); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// IssueAfterCooldown creates a token like Issue unless the user has to wait before
+// another one for purpose may be mailed: at least gap after the last one, and no more
+// than perHour within an hour. Then it returns the wait and no token. The user's row is
+// locked meanwhile, so concurrent requests cannot both get past the check.
+func (ut *UserTokenRepo) IssueAfterCooldown(ctx context.Context, userID uuid.UUID, purpose models.UserTokenPurpose, ttl, gap time.Duration, perHour int) (string, time.Duration, error) {
+	tx, err := ut.db.Begin(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return "", 0, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT EXTRACT(EPOCH FROM LOCALTIMESTAMP - created_at)::float8
+		FROM user_tokens
+		WHERE users_id = $1 AND purpose = $2 AND created_at > LOCALTIMESTAMP - interval '1 hour'
+		ORDER BY created_at DESC
+	`, userID, purpose)
+	if err != nil {
+		return "", 0, err
+	}
+	ages, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (time.Duration, error) {
+		var seconds float64
+		err := row.Scan(&seconds)
+		return time.Duration(seconds * float64(time.Second)), err
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	var wait time.Duration
+	if len(ages) > 0 && ages[0] < gap {
+		wait = gap - ages[0]
+	}
+	if perHour > 0 && len(ages) >= perHour {
+		wait = max(wait, time.Hour-ages[perHour-1])
+	}
+	if wait > 0 {
+		return "", wait, nil
+	}
+
+	token, err := pkg.NewUserToken(string(purpose))
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := tx.Exec(ctx, insertUserToken, userID, purpose, hashToken(token), ttl.Seconds()); err != nil {
+		return "", 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", 0, err
+	}
+	return token, 0, nil
+}
+
+// consumeUserToken marks a token used and returns the user it was issued to.
+func consumeUserToken(ctx context.Context, q querier, purpose models.UserTokenPurpose, token string) (uuid.UUID, error) {
+	if err := pkg.VerifyUserToken(string(purpose), token); err != nil {
+		return uuid.Nil, ErrUserTokenInvalid
+	}
+
+	var userID uuid.UUID
+	err := q.QueryRow(ctx, `
+		UPDATE user_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > LOCALTIMESTAMP
+		RETURNING users_id
+	`, hashToken(token), purpose).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrUserTokenInvalid
+	}
+	return userID, err
+}
+
+// VerifyEmail consumes an email verification token and marks its user verified.
+func (ut *UserTokenRepo) VerifyEmail(ctx context.Context, token string) error {
+	tx, err := ut.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	userID, err := consumeUserToken(ctx, tx, models.UserTokenVerifyEmail, token)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE users SET verified_at = COALESCE(verified_at, NOW()) WHERE id = $1`, userID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ResetPassword consumes a password reset token, sets the new password and returns the
+// user's ID and email. The user's other reset tokens and all of their refresh tokens are
+// revoked, and since only the owner of the address could have used the token, it also
+// counts as verifying it.
+func (ut *UserTokenRepo) ResetPassword(ctx context.Context, token, hashedPassword string) (uuid.UUID, string, error) {
+	tx, err := ut.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	defer tx.Rollback(ctx)
+
+	userID, err := consumeUserToken(ctx, tx, models.UserTokenResetPassword, token)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	var email string
+	err = tx.QueryRow(ctx, `
+		UPDATE users SET password = $2, verified_at = COALESCE(verified_at, NOW()), updated_at = NOW()
+		WHERE id = $1
+		RETURNING email
+	`, userID, hashedPassword).Scan(&email)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE user_tokens SET used_at = NOW()
+		WHERE users_id = $1 AND purpose = $2 AND used_at IS NULL
+	`, userID, models.UserTokenResetPassword)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	if err := revokeAllRefreshTokens(ctx, tx, userID); err != nil {
+		return uuid.Nil, "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, "", err
+	}
+	return userID, email, nil
+}
+
+// UnlockAccount consumes an unlock token and returns the email of the account, whose
+// lockout is kept in Redis by LoginGuard.
+func (ut *UserTokenRepo) UnlockAccount(ctx context.Context, token string) (string, error) {
+	userID, err := consumeUserToken(ctx, ut.db, models.UserTokenUnlockAccount, token)
+	if err != nil {
+		return "", err
+	}
+
+	var email string
+	err = ut.db.QueryRow(ctx, `SELECT email FROM users WHERE id = $1`, userID).Scan(&email)
+	return email, err
+}
+
+// TokenDenylist holds the IDs of revoked access tokens until the tokens expire, and for
+// users whose sessions were all revoked, the time before which their tokens are void.
+type TokenDenylist struct {
+	rdb *redis.Client
+}
+
+func NewTokenDenylist(rdb *redis.Client) *TokenDenylist {
+	return &TokenDenylist{rdb: rdb}
+}
+
+func denylistKey(jti string) string {
+	return "jwt:denylist:" + jti
+}
+
+func userDenylistKey(userID uuid.UUID) string {
+	return "jwt:denylist:user:" + userID.String()
+}
+
+func (td *TokenDenylist) Deny(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if jti == "" || ttl <= 0 {
+		return nil
+	}
+	return td.rdb.Set(ctx, denylistKey(jti), 1, ttl).Err()
+}
+
+// RevokeUser voids every access token issued to the user up to at. Token times only
+// have whole seconds, so tokens from the same second as at are voided too.
+func (td *TokenDenylist) RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	return td.rdb.Set(ctx, userDenylistKey(userID), at.Unix(), pkg.AccessTokenTTL).Err()
+}
+
+func (td *TokenDenylist) IsDenied(ctx context.Context, jti string, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	values, err := td.rdb.MGet(ctx, denylistKey(jti), userDenylistKey(userID)).Result()
+	if err != nil {
+		return false, err
+	}
+	if jti != "" && values[0] != nil {
+		return true, nil
+	}
+
+	if revokedAt, ok := values[1].(string); ok {
+		before, err := strconv.ParseInt(revokedAt, 10, 64)
+		if err != nil {
+			return false, err
+		}
+		return issuedAt.Unix() <= before, nil
+	}
+	return false, nil
+}
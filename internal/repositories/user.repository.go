@@ -2,12 +2,16 @@ package repositories
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/Darari17/be-tickitz-full/internal/models"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -22,10 +26,13 @@ func NewUserRepository(db *pgxpool.Pool) *UserRepository {
 }
 
 func (ur *UserRepository) GetEmail(c context.Context, email string) (*models.User, error) {
-	q := "select id, email, password, role, created_at, updated_at from users where email = $1"
+	q := "select id, email, password, role, verified_at, created_at, updated_at from users where email = $1"
 	user := models.User{}
 
-	if err := ur.db.QueryRow(c, q, email).Scan(&user.ID, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt); err != nil {
+	if err := ur.db.QueryRow(c, q, email).Scan(&user.ID, &user.Email, &user.Password, &user.Role, &user.VerifiedAt, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
 		return nil, err
 	}
 
@@ -137,8 +144,110 @@ func (ur *UserRepository) UpdatePassword(c context.Context, userID uuid.UUID, ha
 	return err
 }
 
+func (ur *UserRepository) IsVerified(c context.Context, userID uuid.UUID) (bool, error) {
+	var verified bool
+	err := ur.db.QueryRow(c, `SELECT verified_at IS NOT NULL FROM users WHERE id = $1`, userID).Scan(&verified)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, ErrUserNotFound
+	}
+	return verified, err
+}
+
 func (ur *UserRepository) UpdateAvatar(c context.Context, userID uuid.UUID, avatar string) error {
 	sql := `UPDATE profile SET avatar = $1, updated_at = NOW() WHERE user_id = $2`
 	_, err := ur.db.Exec(c, sql, avatar, userID)
 	return err
 }
+
+func newCalendarToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// GetCalendarToken returns the token of the user's calendar feed, creating it on first use.
+func (ur *UserRepository) GetCalendarToken(c context.Context, userID uuid.UUID) (string, error) {
+	token, err := newCalendarToken()
+	if err != nil {
+		return "", err
+	}
+
+	sql := `UPDATE users SET calendar_token = COALESCE(calendar_token, $2) WHERE id = $1 RETURNING calendar_token`
+	if err := ur.db.QueryRow(c, sql, userID, token).Scan(&token); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrUserNotFound
+		}
+		return "", err
+	}
+	return token, nil
+}
+
+// ResetCalendarToken replaces the feed token so the old feed URL stops working.
+func (ur *UserRepository) ResetCalendarToken(c context.Context, userID uuid.UUID) (string, error) {
+	token, err := newCalendarToken()
+	if err != nil {
+		return "", err
+	}
+
+	sql := `UPDATE users SET calendar_token = $2, updated_at = NOW() WHERE id = $1`
+	tag, err := ur.db.Exec(c, sql, userID, token)
+	if err != nil {
+		return "", err
+	}
+	if tag.RowsAffected() == 0 {
+		return "", ErrUserNotFound
+	}
+	return token, nil
+}
+
+func (ur *UserRepository) GetUserIDByCalendarToken(c context.Context, token string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	sql := `SELECT id FROM users WHERE calendar_token = $1`
+	if err := ur.db.QueryRow(c, sql, token).Scan(&userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrUserNotFound
+		}
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
+
+func (ur *UserRepository) GetNotificationPreferences(c context.Context, userID uuid.UUID) (*models.NotificationPreferences, error) {
+	var p models.NotificationPreferences
+	sql := `SELECT notify_email, notify_push, notify_sms, reminder_hours FROM profile WHERE user_id = $1`
+	if err := ur.db.QueryRow(c, sql, userID).Scan(&p.Email, &p.Push, &p.SMS, &p.ReminderHours); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// UpdateNotificationPreferences saves the preferences and reschedules the reminders of
+// the user's upcoming bookings to match them.
+func (ur *UserRepository) UpdateNotificationPreferences(c context.Context, userID uuid.UUID, p *models.NotificationPreferences) error {
+	tx, err := ur.db.Begin(c)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(c)
+
+	tag, err := tx.Exec(c, `
+		UPDATE profile SET notify_email = $2, notify_push = $3, notify_sms = $4, reminder_hours = $5, updated_at = NOW()
+		WHERE user_id = $1
+	`, userID, p.Email, p.Push, p.SMS, p.ReminderHours)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	if err := syncReminders(c, tx, userID); err != nil {
+		return err
+	}
+	return tx.Commit(c)
+}
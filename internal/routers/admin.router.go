@@ -6,13 +6,18 @@ import (
 	"github.com/Darari17/be-tickitz-full/internal/repositories"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 )
 
-func initAdminRoutes(r *gin.Engine, db *pgxpool.Pool) {
+func initAdminRoutes(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
 	adminRepo := repositories.NewAdminRepo(db)
 	adminCtrl := controllers.NewAdminController(adminRepo)
+	auditoriumRepo := repositories.NewAuditoriumRepo(db)
+	auditoriumCtrl := controllers.NewAuditoriumController(auditoriumRepo)
+	promoRepo := repositories.NewPromoRepo(db)
+	promoCtrl := controllers.NewPromoController(promoRepo)
 
-	admin := r.Group("/admin", middlewares.RequiredToken, middlewares.Access("admin"))
+	admin := r.Group("/admin", middlewares.RequiredToken(repositories.NewTokenDenylist(rdb)), middlewares.Access("admin"))
 
 	admin.POST("/movies", adminCtrl.CreateMovie)
 	admin.GET("/movies", adminCtrl.GetMovies)
@@ -20,4 +25,19 @@ func initAdminRoutes(r *gin.Engine, db *pgxpool.Pool) {
 	admin.PATCH("/movies/:id", adminCtrl.UpdateMovie)
 	admin.DELETE("/movies/:id", adminCtrl.DeleteMovie)
 
+	admin.PATCH("/users/:id/staff", adminCtrl.AssignStaff)
+
+	admin.GET("/auditoriums", auditoriumCtrl.GetAuditoriums)
+	admin.POST("/auditoriums", auditoriumCtrl.CreateAuditorium)
+	admin.GET("/auditoriums/:id", auditoriumCtrl.GetAuditoriumByID)
+	admin.PATCH("/auditoriums/:id", auditoriumCtrl.UpdateAuditorium)
+	admin.PUT("/auditoriums/:id/layout", auditoriumCtrl.ReplaceLayout)
+	admin.DELETE("/auditoriums/:id", auditoriumCtrl.DeleteAuditorium)
+
+	admin.GET("/promos", promoCtrl.GetPromos)
+	admin.POST("/promos", promoCtrl.CreatePromo)
+	admin.GET("/promos/:id", promoCtrl.GetPromoByID)
+	admin.PUT("/promos/:id", promoCtrl.UpdatePromo)
+	admin.DELETE("/promos/:id", promoCtrl.DeletePromo)
+
 }
@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"github.com/Darari17/be-tickitz-full/internal/controllers"
+	"github.com/Darari17/be-tickitz-full/internal/middlewares"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func initCheckinRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+	checkinRepo := repositories.NewCheckinRepo(db)
+	checkinController := controllers.NewCheckinController(checkinRepo)
+
+	checkinGroup := router.Group("/checkin", middlewares.RequiredToken(repositories.NewTokenDenylist(rdb)), middlewares.Access("staff", "admin"))
+	checkinGroup.POST("", checkinController.Checkin)
+	checkinGroup.GET("/schedules/:id", checkinController.GetAdmissionSummary)
+}
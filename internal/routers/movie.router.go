@@ -1,7 +1,10 @@
 package routers
 
 import (
+	"time"
+
 	"github.com/Darari17/be-tickitz-full/internal/controllers"
+	"github.com/Darari17/be-tickitz-full/internal/middlewares"
 	"github.com/Darari17/be-tickitz-full/internal/repositories"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,7 +15,7 @@ func initMovieRouter(router *gin.Engine, db *pgxpool.Pool, redis *redis.Client)
 	movieRepo := repositories.NewMovieRepository(db, redis)
 	movieHandler := controllers.NewMovieController(movieRepo)
 
-	movies := router.Group("/movies")
+	movies := router.Group("/movies", middlewares.RateLimit(redis, middlewares.RateLimitPolicy{Name: "movies", Limit: 120, Window: time.Minute}))
 	movies.GET("/upcoming", movieHandler.GetUpcomingMovies)
 	movies.GET("/popular", movieHandler.GetPopularMovies)
 	movies.GET("", movieHandler.GetAllMovies)
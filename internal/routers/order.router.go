@@ -1,27 +1,64 @@
 package routers
 
 import (
+	"time"
+
 	"github.com/Darari17/be-tickitz-full/internal/controllers"
 	"github.com/Darari17/be-tickitz-full/internal/middlewares"
+	"github.com/Darari17/be-tickitz-full/internal/payments"
 	"github.com/Darari17/be-tickitz-full/internal/repositories"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 )
 
-func initOrderRouter(router *gin.Engine, db *pgxpool.Pool) {
-	orderRepo := repositories.NewOrderRepo(db)
-	orderController := controllers.NewOrderController(orderRepo)
-
-	orderGroup := router.Group("/orders", middlewares.RequiredToken, middlewares.Access("user"))
-	orderGroup.POST("", orderController.CreateOrder)
-	orderGroup.GET("/history", orderController.GetOrderHistory)
-	orderGroup.GET("/schedules", orderController.GetSchedules)
-	orderGroup.GET("/seats", orderController.GetAvailableSeats)
-	orderGroup.GET("/:id", orderController.GetTransactionDetail)
-
-	orderGroup.GET("/payments", orderController.GetPayments)
-	orderGroup.GET("/cinemas", orderController.GetCinemas)
-	orderGroup.GET("/locations", orderController.GetLocations)
-	orderGroup.GET("/times", orderController.GetTimes)
+func initOrderRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client, providers *payments.Registry) {
+	holdRepo := repositories.NewSeatHoldRepo(rdb)
+	orderRepo := repositories.NewOrderRepo(db, holdRepo)
+	paymentRepo := repositories.NewPaymentRepo(db)
+	orderController := controllers.NewOrderController(orderRepo, holdRepo, paymentRepo, providers)
+	mailController := controllers.NewMailController(repositories.NewMailRepo(db), repositories.NewReminderRepo(db))
+	idempotency := middlewares.Idempotency(rdb)
+	requiredToken := middlewares.RequiredToken(repositories.NewTokenDenylist(rdb))
+
+	// Seat maps have a budget of their own instead of the orders one, so each response
+	// tells the one budget that applies.
+	router.GET("/orders/seats", requiredToken, middlewares.Access("user"),
+		middlewares.RateLimit(rdb, middlewares.RateLimitPolicy{Name: "seats", Limit: 30, Window: time.Minute}), orderController.GetAvailableSeats)
+
+	orderGroup := router.Group("/orders", requiredToken, middlewares.RateLimit(rdb, middlewares.RateLimitPolicy{Name: "orders", Limit: 300, Window: time.Minute}))
+	orderGroup.GET("/:reference", middlewares.Access("user", "admin"), orderController.GetTransactionDetail)
+	orderGroup.GET("/:reference/qrcode", middlewares.Access("user", "admin"), orderController.GetTicketQRCode)
+
+	userOrders := orderGroup.Group("", middlewares.Access("user"))
+	userOrders.POST("", middlewares.Verified(repositories.NewUserRepository(db)), idempotency, orderController.CreateOrder)
+	userOrders.POST("/holds", orderController.CreateHold)
+	userOrders.PATCH("/holds", orderController.ExtendHold)
+	userOrders.DELETE("/holds", orderController.ReleaseHold)
+	userOrders.POST("/quote", orderController.QuotePrice)
+	userOrders.POST("/promo/validate", orderController.ValidatePromo)
+	userOrders.GET("/history", orderController.GetOrderHistory)
+	userOrders.GET("/schedules", orderController.GetSchedules)
+	userOrders.POST("/:reference/cancel", idempotency, orderController.CancelOrder)
+	userOrders.GET("/:reference/ticket.pdf", orderController.GetTicketPDF)
+	userOrders.GET("/:reference/calendar.ics", orderController.GetTicketCalendar)
+
+	userOrders.GET("/payments", orderController.GetPayments)
+	userOrders.GET("/cinemas", orderController.GetCinemas)
+	userOrders.GET("/locations", orderController.GetLocations)
+	userOrders.GET("/times", orderController.GetTimes)
+
+	calendarController := controllers.NewCalendarController(orderRepo, repositories.NewUserRepository(db))
+	router.GET("/calendar/:token/bookings.ics", middlewares.RateLimit(rdb, middlewares.RateLimitPolicy{Name: "calendar", Limit: 60, Window: time.Hour}), calendarController.GetFeed)
+	profileCalendar := router.Group("/profile/calendar", requiredToken, middlewares.Access("user"))
+	profileCalendar.GET("", calendarController.GetFeedURL)
+	profileCalendar.POST("/reset", calendarController.ResetFeedURL)
+
+	adminOrders := router.Group("/admin/orders", requiredToken, middlewares.Access("admin"))
+	adminOrders.PATCH("/:id/status", orderController.UpdateOrderStatus)
+	adminOrders.POST("/:id/cancel", idempotency, orderController.AdminCancelOrder)
+	adminOrders.GET("/:id/emails", mailController.GetOrderEmails)
+	adminOrders.POST("/:id/emails/resend", mailController.ResendTicketEmail)
+	adminOrders.GET("/:id/reminders", mailController.GetOrderReminders)
 
 }
@@ -0,0 +1,67 @@
+package routers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/controllers"
+	"github.com/Darari17/be-tickitz-full/internal/middlewares"
+	"github.com/Darari17/be-tickitz-full/internal/payments"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func initPaymentProviders() (*payments.Registry, *payments.FakeProvider) {
+	var providers []payments.Provider
+	var fake *payments.FakeProvider
+
+	if utils.GetEnvBool("PAYMENT_FAKE_ENABLED", false) {
+		provider, err := payments.NewFakeProvider()
+		if err != nil {
+			log.Println("Fake payment provider disabled.\nCause:", err.Error())
+		} else {
+			fake = provider
+			providers = append(providers, fake)
+		}
+	}
+
+	return payments.NewRegistry(providers...), fake
+}
+
+func initPaymentRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client, providers *payments.Registry, fake *payments.FakeProvider) {
+	paymentRepo := repositories.NewPaymentRepo(db)
+	paymentController := controllers.NewPaymentController(paymentRepo, providers, fake)
+	warnUnavailableMethods(paymentRepo, providers)
+
+	// Provider webhooks come from a few addresses, so the budget per address is generous.
+	paymentGroup := router.Group("/payments", middlewares.RateLimit(rdb, middlewares.RateLimitPolicy{Name: "payments", Limit: 600, Window: time.Minute}))
+	paymentGroup.POST("/webhook/:provider", paymentController.Webhook)
+
+	if fake != nil {
+		paymentGroup.GET("/fake/:reference/pay", paymentController.FakePay)
+	}
+}
+
+// warnUnavailableMethods logs every payment method orders are rejected for, so a missing
+// provider mapping shows at startup rather than as failed checkouts.
+func warnUnavailableMethods(paymentRepo *repositories.PaymentRepo, providers *payments.Registry) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	methods, err := paymentRepo.GetUnavailableMethods(ctx, providers.Names())
+	if err != nil {
+		log.Println("GetUnavailableMethods error:", err)
+		return
+	}
+	for _, m := range methods {
+		if m.Provider == "" {
+			log.Printf("Payment method %d (%s) has no provider and cannot be paid with\n", m.ID, m.Name)
+		} else {
+			log.Printf("Payment method %d (%s) uses provider %s, which is not enabled\n", m.ID, m.Name, m.Provider)
+		}
+	}
+}
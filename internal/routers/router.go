@@ -1,7 +1,10 @@
 package routers
 
 import (
+	"log"
 	"net/http"
+	"os"
+	"strings"
 
 	docs "github.com/Darari17/be-tickitz-full/docs"
 	"github.com/Darari17/be-tickitz-full/internal/dtos"
@@ -15,12 +18,26 @@ import (
 
 func InitRouter(db *pgxpool.Pool, rdb *redis.Client) *gin.Engine {
 	router := gin.Default()
+	// Client addresses for login protection and rate limits come from X-Forwarded-For
+	// only when sent by one of TRUSTED_PROXIES. Without it the header is ignored, since
+	// anyone could set it.
+	var proxies []string
+	if value := os.Getenv("TRUSTED_PROXIES"); value != "" {
+		proxies = strings.Split(value, ",")
+	}
+	if err := router.SetTrustedProxies(proxies); err != nil {
+		log.Println("Invalid TRUSTED_PROXIES, trusting no proxy.\nCause:", err.Error())
+		router.SetTrustedProxies(nil)
+	}
 	router.Use(middlewares.CORSMiddleware)
 
-	initAuthRouter(router, db)
+	initAuthRouter(router, db, rdb)
 	initMovieRouter(router, db, rdb)
-	initOrderRouter(router, db)
-	initAdminRoutes(router, db)
+	paymentProviders, fakePayments := initPaymentProviders()
+	initOrderRouter(router, db, rdb, paymentProviders)
+	initPaymentRouter(router, db, rdb, paymentProviders, fakePayments)
+	initCheckinRouter(router, db, rdb)
+	initAdminRoutes(router, db, rdb)
 
 	router.Static("/img", "public")
 
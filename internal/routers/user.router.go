@@ -1,24 +1,43 @@
 package routers
 
 import (
+	"time"
+
 	"github.com/Darari17/be-tickitz-full/internal/controllers"
+	"github.com/Darari17/be-tickitz-full/internal/mailer"
 	"github.com/Darari17/be-tickitz-full/internal/middlewares"
 	"github.com/Darari17/be-tickitz-full/internal/repositories"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 )
 
-func initAuthRouter(router *gin.Engine, db *pgxpool.Pool) {
+func initAuthRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
 	authRepo := repositories.NewUserRepository(db)
-	authHandler := controllers.NewUserController(authRepo)
+	denylist := repositories.NewTokenDenylist(rdb)
+	authHandler := controllers.NewUserController(authRepo, repositories.NewRefreshTokenRepo(db), denylist, repositories.NewUserTokenRepo(db), repositories.NewLoginGuard(rdb), mailer.NewSMTPTransport())
+	requiredToken := middlewares.RequiredToken(denylist)
+	pointHandler := controllers.NewPointController(repositories.NewPointRepo(db))
+
+	router.GET("/.well-known/jwks.json", authHandler.GetJWKS)
 
-	auth := router.Group("/auth")
+	auth := router.Group("/auth", middlewares.RateLimit(rdb, middlewares.RateLimitPolicy{Name: "auth", Limit: 30, Window: time.Minute, FailClosed: true}))
 	auth.POST("/login", authHandler.Login)
 	auth.POST("/register", authHandler.Register)
+	auth.GET("/verify", authHandler.VerifyEmail)
+	auth.POST("/verify/resend", authHandler.ResendVerification)
+	auth.POST("/forgot-password", authHandler.ForgotPassword)
+	auth.POST("/reset-password", authHandler.ResetPassword)
+	auth.GET("/unlock", authHandler.UnlockAccount)
+	auth.POST("/refresh", authHandler.Refresh)
+	auth.POST("/logout", requiredToken, authHandler.Logout)
 
-	profile := router.Group("/profile", middlewares.RequiredToken, middlewares.Access("user"))
+	profile := router.Group("/profile", requiredToken, middlewares.Access("user"))
 	profile.GET("", authHandler.GetProfile)
 	profile.PATCH("", authHandler.UpdateProfile)
 	profile.PATCH("/change-password", authHandler.ChangePassword)
 	profile.PATCH("/change-avatar", authHandler.ChangeAvatar)
+	profile.GET("/points", pointHandler.GetPointHistory)
+	profile.GET("/notifications", authHandler.GetNotificationPreferences)
+	profile.PATCH("/notifications", authHandler.UpdateNotificationPreferences)
 }
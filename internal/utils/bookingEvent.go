@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"strings"
+	"time"
+	_ "time/tzdata" // cinema time zones must resolve on hosts without a zoneinfo database
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/pkg"
+)
+
+// CinemaLocation is the time zone schedule dates and times are written in, taken from
+// CINEMA_TIMEZONE and Asia/Jakarta by default.
+func CinemaLocation() *time.Location {
+	name := os.Getenv("CINEMA_TIMEZONE")
+	if name == "" {
+		name = "Asia/Jakarta"
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("Invalid time zone for CINEMA_TIMEZONE: %q, using server local time\n", name)
+		return time.Local
+	}
+	return loc
+}
+
+// BookingEvent turns an order into a calendar event spanning its showtime. Movies
+// without a known duration are given two hours.
+func BookingEvent(order *models.OrderDetail, loc *time.Location) (pkg.CalendarEvent, error) {
+	showTime, err := time.Parse("15:04:05", order.TimeStr)
+	if err != nil {
+		if showTime, err = time.Parse("15:04", order.TimeStr); err != nil {
+			return pkg.CalendarEvent{}, err
+		}
+	}
+	start := time.Date(order.Date.Year(), order.Date.Month(), order.Date.Day(),
+		showTime.Hour(), showTime.Minute(), showTime.Second(), 0, loc)
+
+	duration := time.Duration(order.Movie.Duration) * time.Minute
+	if duration <= 0 {
+		duration = 2 * time.Hour
+	}
+
+	seats := make([]string, 0, len(order.Seats))
+	for _, s := range order.ActiveSeats() {
+		seats = append(seats, s.SeatCode)
+	}
+
+	return pkg.CalendarEvent{
+		UID:         "order-" + order.Reference + "@tickitz",
+		Start:       start,
+		End:         start.Add(duration),
+		Summary:     order.Movie.Title,
+		Location:    order.CinemaName + ", " + order.Location,
+		Description: "Seats: " + strings.Join(seats, ", ") + "\nBooking reference: " + order.Reference,
+		URL:         GetBaseURL() + "/orders/" + order.Reference,
+		Reminder:    time.Hour,
+	}, nil
+}
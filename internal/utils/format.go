@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FormatRupiah writes an amount the Indonesian way, e.g. "Rp 45.000".
+func FormatRupiah(amount int64) string {
+	digits := strconv.FormatInt(max(amount, -amount), 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	if amount < 0 {
+		return "-Rp " + b.String()
+	}
+	return "Rp " + b.String()
+}
+
+// FormatShowTime trims the seconds off a time of day such as "13:00:00".
+func FormatShowTime(t string) string {
+	if len(t) > 5 {
+		return t[:5]
+	}
+	return t
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func GetEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid duration for %s: %q, using %s\n", key, value, fallback)
+		return fallback
+	}
+
+	return d
+}
+
+func GetEnvBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid boolean for %s: %q, using %t\n", key, value, fallback)
+		return fallback
+	}
+
+	return b
+}
+
+func GetEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid integer for %s: %q, using %d\n", key, value, fallback)
+		return fallback
+	}
+
+	return i
+}
+
+func GetEnvFloat(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		log.Printf("Invalid number for %s: %q, using %g\n", key, value, fallback)
+		return fallback
+	}
+
+	return f
+}
+
+// GetBaseURL is the public address of this server, used in links sent to users.
+func GetBaseURL() string {
+	baseURL := os.Getenv("APP_BASE_URL")
+	if baseURL == "" {
+		return "http://localhost:8080"
+	}
+	return strings.TrimRight(baseURL, "/")
+}
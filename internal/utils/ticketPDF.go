@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/pkg"
+)
+
+var (
+	ticketInk    = color.RGBA{R: 0x14, G: 0x14, B: 0x2b, A: 0xff}
+	ticketMuted  = color.RGBA{R: 0x6e, G: 0x71, B: 0x91, A: 0xff}
+	ticketAccent = color.RGBA{R: 0x5f, G: 0x2e, B: 0xea, A: 0xff}
+	ticketRule   = color.RGBA{R: 0xde, G: 0xde, B: 0xde, A: 0xff}
+	ticketWhite  = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+)
+
+const ticketMargin = 40.0
+
+// TicketPDF renders a printable A4 ticket of an order. The poster is read from
+// publicDir and left out when it is missing or in a format the standard library
+// cannot decode.
+func TicketPDF(order *models.OrderDetail, publicDir string) ([]byte, error) {
+	qr, err := pkg.NewQRCode(order.QRCode)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := pkg.NewPDF(pkg.PageA4Width, pkg.PageA4Height)
+	doc.AddPage()
+
+	doc.Rect(0, 0, pkg.PageA4Width, 70, ticketAccent)
+	doc.Text(ticketMargin, 44, pkg.HelveticaBold, 24, ticketWhite, "Tickitz")
+	label := "E-TICKET " + order.Reference
+	doc.Text(pkg.PageA4Width-ticketMargin-doc.TextWidth(pkg.HelveticaBold, 12, label), 42, pkg.HelveticaBold, 12, ticketWhite, label)
+
+	top := 100.0
+	left := ticketMargin
+	if drawPoster(doc, order.Movie.Poster, publicDir, ticketMargin, top, 150, 225) {
+		left += 170
+	}
+	column := pkg.PageA4Width - ticketMargin - left
+
+	y := top + 20
+	for _, line := range wrapText(doc, pkg.HelveticaBold, 20, order.Movie.Title, column) {
+		doc.Text(left, y, pkg.HelveticaBold, 20, ticketInk, line)
+		y += 24
+	}
+	y += 8
+
+	seats := make([]string, 0, len(order.Seats))
+	for _, s := range order.ActiveSeats() {
+		seats = append(seats, s.SeatCode)
+	}
+	details := [][2]string{
+		{"Cinema", order.CinemaName},
+		{"Location", order.Location},
+		{"Date", order.Date.Format("Monday, 2 January 2006")},
+		{"Time", FormatShowTime(order.TimeStr)},
+		{"Seats", strings.Join(seats, ", ")},
+		{"Name", order.FullName},
+		{"Payment", order.PaymentName},
+	}
+	for _, d := range details {
+		doc.Text(left, y, pkg.Helvetica, 9, ticketMuted, strings.ToUpper(d[0]))
+		y += 14
+		for _, line := range wrapText(doc, pkg.HelveticaBold, 12, d[1], column) {
+			doc.Text(left, y, pkg.HelveticaBold, 12, ticketInk, line)
+			y += 15
+		}
+		y += 5
+	}
+
+	y = max(y, top+225) + 25
+	const qrSize = 200.0
+	doc.Image(qr.Image(8), (pkg.PageA4Width-qrSize)/2, y, qrSize, qrSize)
+	y += qrSize + 16
+	note := "Show this code at the entrance"
+	doc.Text((pkg.PageA4Width-doc.TextWidth(pkg.Helvetica, 10, note))/2, y, pkg.Helvetica, 10, ticketMuted, note)
+	y += 36
+
+	doc.Text(ticketMargin, y, pkg.HelveticaBold, 14, ticketInk, "Receipt")
+	y += 20
+	for _, s := range order.ActiveSeats() {
+		if y > pkg.PageA4Height-ticketMargin-80 {
+			doc.AddPage()
+			y = ticketMargin + 20
+		}
+		receiptLine(doc, y, pkg.Helvetica, "Seat "+s.SeatCode+" ("+s.SeatClass+")", FormatRupiah(s.Price))
+		y += 18
+	}
+	doc.Line(ticketMargin, y-12, pkg.PageA4Width-ticketMargin, y-12, 0.5, ticketRule)
+	y += 4
+	receiptLine(doc, y, pkg.Helvetica, "Subtotal", FormatRupiah(order.Subtotal))
+	y += 18
+	receiptLine(doc, y, pkg.Helvetica, "Fees", FormatRupiah(order.Fees))
+	y += 18
+	if order.Discount > 0 {
+		receiptLine(doc, y, pkg.Helvetica, "Discount", FormatRupiah(-order.Discount))
+		y += 18
+	}
+	receiptLine(doc, y, pkg.HelveticaBold, "Total", FormatRupiah(order.Total))
+
+	return doc.Bytes(), nil
+}
+
+func receiptLine(doc *pkg.PDF, y float64, font pkg.PDFFont, label, amount string) {
+	doc.Text(ticketMargin, y, font, 11, ticketInk, label)
+	doc.Text(pkg.PageA4Width-ticketMargin-doc.TextWidth(font, 11, amount), y, font, 11, ticketInk, amount)
+}
+
+func drawPoster(doc *pkg.PDF, poster, publicDir string, x, y, w, h float64) bool {
+	if poster == "" || strings.Contains(poster, "://") {
+		return false
+	}
+
+	data, err := os.ReadFile(filepath.Join(publicDir, filepath.Base(poster)))
+	if err != nil {
+		log.Println("Read poster error:", err)
+		return false
+	}
+
+	if err := doc.JPEG(data, x, y, w, h); err == nil {
+		return true
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		log.Println("Decode poster error:", err)
+		return false
+	}
+	doc.Image(img, x, y, w, h)
+	return true
+}
+
+// wrapText breaks text into lines no wider than width, splitting on spaces.
+func wrapText(doc *pkg.PDF, font pkg.PDFFont, size float64, text string, width float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		next := word
+		if line != "" {
+			next = line + " " + word
+		}
+		if line != "" && doc.TextWidth(font, size, next) > width {
+			lines = append(lines, line)
+			next = word
+		}
+		line = next
+	}
+	return append(lines, line)
+}
@@ -0,0 +1,41 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/events"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+)
+
+const expiryBatchSize = 100
+
+// ExpireOrders expires orders left unpaid for longer than window, drops any seat holds
+// their owners still have on those seats and publishes an order.expired event for each.
+func ExpireOrders(orderRepo *repositories.OrderRepo, holdRepo *repositories.SeatHoldRepo, publisher *events.Publisher, window time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		for {
+			expired, err := orderRepo.ExpirePendingOrders(ctx, window, expiryBatchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, o := range expired {
+				if err := holdRepo.Release(ctx, o.ScheduleID, o.SeatCodes, o.UserID); err != nil {
+					log.Println("Release hold error:", err)
+				}
+				if err := publisher.Publish(ctx, events.OrderExpired, o); err != nil {
+					log.Println("Publish event error:", err)
+				}
+			}
+
+			if len(expired) > 0 {
+				log.Printf("Expired %d unpaid orders\n", len(expired))
+			}
+			if len(expired) < expiryBatchSize {
+				return nil
+			}
+		}
+	}
+}
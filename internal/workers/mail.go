@@ -0,0 +1,79 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/mailer"
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+)
+
+const mailBatchSize = 20
+
+// DeliverEmails sends the emails waiting in the outbox. A failed email is retried with
+// a doubling delay, starting at a minute and capped at an hour, until maxAttempts.
+func DeliverEmails(mailRepo *repositories.MailRepo, orderRepo *repositories.OrderRepo, transport mailer.Transport, baseURL string, maxAttempts int) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		emails, err := mailRepo.ClaimDue(ctx, mailBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, email := range emails {
+			subject, err := sendEmail(ctx, orderRepo, transport, baseURL, email)
+			var unpaid *unpaidOrderError
+			if errors.As(err, &unpaid) {
+				if err := mailRepo.MarkCancelled(ctx, email.ID, unpaid.Error()); err != nil {
+					log.Println("MarkCancelled error:", err)
+				}
+				continue
+			}
+			if err == nil {
+				if err := mailRepo.MarkSent(ctx, email.ID, subject); err != nil {
+					log.Println("MarkSent error:", err)
+				}
+				continue
+			}
+
+			log.Printf("Email %d for order %s failed (attempt %d).\nCause: %s\n", email.ID, email.Reference, email.Attempts, err.Error())
+			var retryAt *time.Time
+			if email.Attempts < maxAttempts {
+				at := time.Now().Add(min(time.Minute<<(email.Attempts-1), time.Hour))
+				retryAt = &at
+			}
+			if err := mailRepo.MarkFailed(ctx, email.ID, err.Error(), retryAt); err != nil {
+				log.Println("MarkFailed error:", err)
+			}
+		}
+		return nil
+	}
+}
+
+// unpaidOrderError stops the ticket of an order that is no longer paid from going out.
+type unpaidOrderError struct {
+	status models.OrderStatus
+}
+
+func (e *unpaidOrderError) Error() string {
+	return "Order is " + string(e.status)
+}
+
+func sendEmail(ctx context.Context, orderRepo *repositories.OrderRepo, transport mailer.Transport, baseURL string, email models.EmailDelivery) (string, error) {
+	order, err := orderRepo.GetTransactionDetail(ctx, email.Reference)
+	if err != nil {
+		return "", err
+	}
+	if order.Status != models.OrderStatusPaid && order.Status != models.OrderStatusCheckedIn {
+		return "", &unpaidOrderError{status: order.Status}
+	}
+	order.Email = email.Recipient
+
+	msg, err := mailer.TicketEmail(order, baseURL)
+	if err != nil {
+		return "", err
+	}
+	return msg.Subject, transport.Send(ctx, msg)
+}
@@ -0,0 +1,81 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/notify"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+)
+
+const reminderBatchSize = 50
+
+// SendReminders delivers the showtime reminders that are due. A failed reminder is
+// retried with a doubling delay capped at 15 minutes, but never after the show starts.
+func SendReminders(reminderRepo *repositories.ReminderRepo, orderRepo *repositories.OrderRepo, channels *notify.Registry, maxAttempts int) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		reminders, err := reminderRepo.ClaimDue(ctx, reminderBatchSize)
+		if err != nil {
+			return err
+		}
+
+		loc := utils.CinemaLocation()
+		for _, r := range reminders {
+			order, err := orderRepo.GetTransactionDetail(ctx, r.Reference)
+			if err != nil {
+				log.Println("GetTransactionDetail error:", err)
+				retryAt := time.Now().Add(time.Minute)
+				if err := reminderRepo.MarkFailed(ctx, r.ID, err.Error(), &retryAt); err != nil {
+					log.Println("MarkFailed error:", err)
+				}
+				continue
+			}
+
+			event, err := utils.BookingEvent(order, loc)
+			reason := ""
+			switch {
+			case order.Status != models.OrderStatusPaid:
+				reason = "Order is " + string(order.Status)
+			case err == nil && !time.Now().Before(event.Start):
+				reason = "Show already started"
+			}
+			if reason != "" {
+				if err := reminderRepo.MarkCancelled(ctx, r.ID, reason); err != nil {
+					log.Println("MarkCancelled error:", err)
+				}
+				continue
+			}
+
+			channel, ok := channels.Get(r.Channel)
+			if !ok {
+				if err := reminderRepo.MarkFailed(ctx, r.ID, "channel "+r.Channel+" is not configured", nil); err != nil {
+					log.Println("MarkFailed error:", err)
+				}
+				continue
+			}
+
+			err = channel.Send(ctx, notify.ShowtimeReminder(order))
+			if err == nil {
+				if err := reminderRepo.MarkSent(ctx, r.ID); err != nil {
+					log.Println("MarkSent error:", err)
+				}
+				continue
+			}
+
+			log.Printf("Reminder %d for order %s on %s failed (attempt %d).\nCause: %s\n", r.ID, r.Reference, r.Channel, r.Attempts, err.Error())
+			var retryAt *time.Time
+			at := time.Now().Add(min(time.Minute<<(r.Attempts-1), 15*time.Minute))
+			if r.Attempts < maxAttempts && !errors.Is(err, notify.ErrNoAddress) && (event.Start.IsZero() || at.Before(event.Start)) {
+				retryAt = &at
+			}
+			if err := reminderRepo.MarkFailed(ctx, r.ID, err.Error(), retryAt); err != nil {
+				log.Println("MarkFailed error:", err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,128 @@
+package workers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// take or renew the lease when it is free or already ours
+var acquireLeaseScript = redis.NewScript(`
+local owner = redis.call('GET', KEYS[1])
+if not owner then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	return 1
+end
+if owner == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+var releaseLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs jobs on an interval. Every job has a lease in Redis, so when several
+// replicas run the same scheduler only the one holding the lease runs the job. A lease
+// lasts two intervals; if its holder dies another replica takes over after that.
+type Scheduler struct {
+	rdb    *redis.Client
+	id     string
+	jobs   []Job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(rdb *redis.Client) *Scheduler {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return &Scheduler{rdb: rdb, id: host + "-" + hex.EncodeToString(suffix)}
+}
+
+func (s *Scheduler) Add(name string, interval time.Duration, run func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, Job{Name: name, Interval: interval, Run: run})
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Stop waits for running jobs to finish and gives up the leases held by this replica.
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, job := range s.jobs {
+		if err := releaseLeaseScript.Run(ctx, s.rdb, []string{leaseKey(job.Name)}, s.id).Err(); err != nil {
+			log.Println("Scheduler Redis Error\nCause:", err.Error())
+		}
+	}
+}
+
+func leaseKey(name string) string {
+	return "worker:lease:" + name
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context, job Job) {
+	leader, err := acquireLeaseScript.Run(ctx, s.rdb, []string{leaseKey(job.Name)}, s.id, (2 * job.Interval).Milliseconds()).Bool()
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Println("Scheduler Redis Error\nCause:", err.Error())
+		}
+		return
+	}
+	if !leader {
+		return
+	}
+
+	// a run in progress is allowed to finish when the scheduler stops
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), job.Interval)
+	defer cancel()
+	if err := job.Run(runCtx); err != nil {
+		log.Printf("Job %s failed.\nCause: %s\n", job.Name, err.Error())
+	}
+}
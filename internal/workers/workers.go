@@ -0,0 +1,43 @@
+package workers
+
+import (
+	"time"
+
+	"github.com/Darari17/be-tickitz-full/internal/events"
+	"github.com/Darari17/be-tickitz-full/internal/mailer"
+	"github.com/Darari17/be-tickitz-full/internal/models"
+	"github.com/Darari17/be-tickitz-full/internal/notify"
+	"github.com/Darari17/be-tickitz-full/internal/repositories"
+	"github.com/Darari17/be-tickitz-full/internal/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitScheduler(db *pgxpool.Pool, rdb *redis.Client) *Scheduler {
+	scheduler := NewScheduler(rdb)
+	publisher := events.NewPublisher(rdb)
+
+	holdRepo := repositories.NewSeatHoldRepo(rdb)
+	orderRepo := repositories.NewOrderRepo(db, holdRepo)
+	scheduler.Add("order-expiry",
+		utils.GetEnvDuration("ORDER_EXPIRY_INTERVAL", time.Minute),
+		ExpireOrders(orderRepo, holdRepo, publisher, utils.GetEnvDuration("ORDER_PAYMENT_WINDOW", 15*time.Minute)),
+	)
+
+	transport := mailer.NewSMTPTransport()
+	scheduler.Add("email-delivery",
+		utils.GetEnvDuration("EMAIL_INTERVAL", 30*time.Second),
+		DeliverEmails(repositories.NewMailRepo(db), orderRepo, transport, utils.GetBaseURL(), utils.GetEnvInt("EMAIL_MAX_ATTEMPTS", 5)),
+	)
+
+	channels := []notify.Channel{notify.NewEmailChannel(transport)}
+	if utils.GetEnvBool("NOTIFY_FAKE_ENABLED", false) {
+		channels = append(channels, notify.NewFakeChannel(models.ChannelPush), notify.NewFakeChannel(models.ChannelSMS))
+	}
+	scheduler.Add("showtime-reminders",
+		utils.GetEnvDuration("REMINDER_INTERVAL", time.Minute),
+		SendReminders(repositories.NewReminderRepo(db), orderRepo, notify.NewRegistry(channels...), utils.GetEnvInt("REMINDER_MAX_ATTEMPTS", 3)),
+	)
+
+	return scheduler
+}
@@ -0,0 +1,126 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const icalProdID = "-//Tickitz//Bookings//EN"
+
+// CalendarEvent is a VEVENT. Times are written in UTC so no VTIMEZONE is needed.
+type CalendarEvent struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Location    string
+	Description string
+	URL         string
+	// Reminder is how long before Start a display alarm goes off, none when zero.
+	Reminder time.Duration
+}
+
+// Calendar is an RFC 5545 VCALENDAR.
+type Calendar struct {
+	Name   string
+	Events []CalendarEvent
+	// Refresh hints subscribed clients how often to poll the feed, unset when zero.
+	Refresh time.Duration
+}
+
+// ICS serializes the calendar with every event stamped at now.
+func (c *Calendar) ICS(now time.Time) []byte {
+	var b bytes.Buffer
+	line := func(name, value string) {
+		icalFold(&b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", icalProdID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", icalEscape(c.Name))
+	}
+	if c.Refresh > 0 {
+		line("REFRESH-INTERVAL;VALUE=DURATION", icalDuration(c.Refresh))
+		line("X-PUBLISHED-TTL", icalDuration(c.Refresh))
+	}
+
+	for _, e := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", icalTime(now))
+		line("DTSTART", icalTime(e.Start))
+		line("DTEND", icalTime(e.End))
+		line("SUMMARY", icalEscape(e.Summary))
+		if e.Location != "" {
+			line("LOCATION", icalEscape(e.Location))
+		}
+		if e.Description != "" {
+			line("DESCRIPTION", icalEscape(e.Description))
+		}
+		if e.URL != "" {
+			line("URL", e.URL)
+		}
+		line("STATUS", "CONFIRMED")
+		line("TRANSP", "OPAQUE")
+		if e.Reminder > 0 {
+			line("BEGIN", "VALARM")
+			line("ACTION", "DISPLAY")
+			line("DESCRIPTION", icalEscape(e.Summary))
+			line("TRIGGER", "-"+icalDuration(e.Reminder))
+			line("END", "VALARM")
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return b.Bytes()
+}
+
+func icalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icalDuration writes a positive duration as e.g. PT1H30M, dropping seconds.
+func icalDuration(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	s := "PT"
+	if minutes >= 60 {
+		s += fmt.Sprintf("%dH", minutes/60)
+	}
+	if minutes%60 != 0 || minutes < 60 {
+		s += fmt.Sprintf("%dM", minutes%60)
+	}
+	return s
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func icalEscape(text string) string {
+	return icalEscaper.Replace(text)
+}
+
+// icalFold writes a content line, folding it every 75 octets without splitting a
+// UTF-8 sequence.
+func icalFold(b *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit.
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
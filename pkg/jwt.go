@@ -1,7 +1,6 @@
 package pkg
 
 import (
-	"errors"
 	"os"
 	"time"
 
@@ -16,37 +15,41 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
+// AccessTokenTTL is how long an access token is valid.
+const AccessTokenTTL = time.Hour
+
+// NewJWTClaims describes a one-hour access token. Every token gets its own ID so a
+// single token can be revoked on logout.
 func NewJWTClaims(u uuid.UUID, e string, r string) *Claims {
+	now := time.Now()
 	return &Claims{
 		UserID: u,
 		Email:  e,
 		Role:   r,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 1)),
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
 			Issuer:    os.Getenv("JWT_ISSUER"),
 		},
 	}
 }
 
 func (c *Claims) GenerateToken() (string, error) {
-	secretKey := os.Getenv("JWT_SECRET")
-	if secretKey == "" {
-		return "", errors.New("no secret keys found")
+	kr, err := CurrentKeyring()
+	if err != nil {
+		return "", err
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
-	return token.SignedString([]byte(secretKey))
+	return kr.Sign(c)
 }
 
 func (c *Claims) VerifyToken(token string) error {
-	secretKey := os.Getenv("JWT_SECRET")
-	if secretKey == "" {
-		return errors.New("no secret keys found")
+	kr, err := CurrentKeyring()
+	if err != nil {
+		return err
 	}
 
-	parsedToken, err := jwt.ParseWithClaims(token, c, func(t *jwt.Token) (any, error) {
-		return []byte(secretKey), nil
-	})
+	parsedToken, err := kr.Parse(token, c)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,281 @@
+package pkg
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoSigningKey = errors.New("no signing key configured")
+	ErrUnknownKey   = errors.New("token signed with an unknown or retired key")
+)
+
+// SigningKey is one key of the keyring. A key with RetiredAt still verifies tokens
+// until then, which lets tokens signed before a rotation live out their lifetime.
+type SigningKey struct {
+	ID        string
+	Method    jwt.SigningMethod
+	RetiredAt *time.Time
+
+	sign   any
+	verify any
+}
+
+func (k *SigningKey) retired(now time.Time) bool {
+	return k.RetiredAt != nil && !now.Before(*k.RetiredAt)
+}
+
+// Keyring holds the key new tokens are signed with and the keys tokens are verified
+// against, selected by the kid header.
+type Keyring struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+	// legacy verifies HS256 tokens without a kid, issued before keys were rotated.
+	legacy *SigningKey
+}
+
+// keyringManifest is keys.json in the key directory, e.g.
+//
+//	{"active": "2026-10", "keys": [
+//	  {"kid": "2026-10", "file": "2026-10.pem"},
+//	  {"kid": "2026-04", "file": "2026-04.pem", "retired_at": "2026-10-16T12:00:00Z"}]}
+type keyringManifest struct {
+	Active string `json:"active"`
+	Keys   []struct {
+		ID        string     `json:"kid"`
+		File      string     `json:"file"`
+		RetiredAt *time.Time `json:"retired_at"`
+	} `json:"keys"`
+}
+
+// LoadKeyring reads the manifest and PEM private keys (PKCS#8 RSA or Ed25519, or
+// PKCS#1 RSA) from dir.
+func LoadKeyring(dir string) (*Keyring, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, "keys.json"))
+	if err != nil {
+		return nil, err
+	}
+	var manifest keyringManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("keys.json: %w", err)
+	}
+
+	kr := &Keyring{keys: map[string]*SigningKey{}}
+	for _, entry := range manifest.Keys {
+		if entry.ID == "" {
+			return nil, errors.New("keys.json: key without kid")
+		}
+		if _, dup := kr.keys[entry.ID]; dup {
+			return nil, fmt.Errorf("keys.json: duplicate kid %q", entry.ID)
+		}
+
+		pemBytes, err := os.ReadFile(filepath.Join(dir, filepath.Base(entry.File)))
+		if err != nil {
+			return nil, err
+		}
+		key, err := parseSigningKey(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", entry.ID, err)
+		}
+		key.ID = entry.ID
+		key.RetiredAt = entry.RetiredAt
+		kr.keys[key.ID] = key
+	}
+
+	kr.active = kr.keys[manifest.Active]
+	if kr.active == nil {
+		return nil, fmt.Errorf("keys.json: active key %q not found", manifest.Active)
+	}
+	if kr.active.RetiredAt != nil {
+		return nil, fmt.Errorf("keys.json: active key %q is retired", manifest.Active)
+	}
+	return kr, nil
+}
+
+func parseSigningKey(pemBytes []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	if block.Type == "RSA PRIVATE KEY" {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < 2048 {
+			return nil, errors.New("RSA keys must be at least 2048 bits")
+		}
+		return &SigningKey{Method: jwt.SigningMethodRS256, sign: key, verify: &key.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{Method: jwt.SigningMethodEdDSA, sign: key, verify: key.Public()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
+
+func hmacKey(secret string) *SigningKey {
+	return &SigningKey{Method: jwt.SigningMethodHS256, sign: []byte(secret), verify: []byte(secret)}
+}
+
+// KeyringFromEnv loads the keyring from JWT_KEYS_DIR. JWT_SECRET is kept as the HS256
+// key of tokens without a kid: it signs too when no directory is set. Otherwise it only
+// verifies, so that switching to asymmetric keys does not log everyone out, and retires
+// at JWT_SECRET_RETIRE_AT, or once the tokens it signed before startup have expired.
+func KeyringFromEnv() (*Keyring, error) {
+	secret := os.Getenv("JWT_SECRET")
+
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		if secret == "" {
+			return nil, ErrNoSigningKey
+		}
+		legacy := hmacKey(secret)
+		return &Keyring{active: legacy, keys: map[string]*SigningKey{}, legacy: legacy}, nil
+	}
+
+	kr, err := LoadKeyring(dir)
+	if err != nil {
+		return nil, err
+	}
+	if secret != "" {
+		retireAt := time.Now().Add(AccessTokenTTL)
+		if value := os.Getenv("JWT_SECRET_RETIRE_AT"); value != "" {
+			if retireAt, err = time.Parse(time.RFC3339, value); err != nil {
+				return nil, fmt.Errorf("JWT_SECRET_RETIRE_AT: %w", err)
+			}
+		}
+		kr.legacy = hmacKey(secret)
+		kr.legacy.RetiredAt = &retireAt
+	}
+	return kr, nil
+}
+
+var (
+	keyringMu      sync.Mutex
+	defaultKeyring *Keyring
+)
+
+// SetKeyring replaces the keyring tokens are signed and verified with.
+func SetKeyring(kr *Keyring) {
+	keyringMu.Lock()
+	defer keyringMu.Unlock()
+	defaultKeyring = kr
+}
+
+// CurrentKeyring returns the keyring set with SetKeyring, loading it from the
+// environment on first use otherwise.
+func CurrentKeyring() (*Keyring, error) {
+	keyringMu.Lock()
+	defer keyringMu.Unlock()
+	if defaultKeyring == nil {
+		kr, err := KeyringFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		defaultKeyring = kr
+	}
+	return defaultKeyring, nil
+}
+
+// Sign signs claims with the active key, naming it in the kid header.
+func (kr *Keyring) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(kr.active.Method, claims)
+	if kr.active != kr.legacy {
+		token.Header["kid"] = kr.active.ID
+	}
+	return token.SignedString(kr.active.sign)
+}
+
+// Parse verifies a token against the key named by its kid, or the legacy HS256 key
+// when it has none, and fills claims.
+func (kr *Keyring) Parse(token string, claims jwt.Claims) (*jwt.Token, error) {
+	methods := []string{}
+	for _, k := range kr.keys {
+		methods = append(methods, k.Method.Alg())
+	}
+	if kr.legacy != nil {
+		methods = append(methods, kr.legacy.Method.Alg())
+	}
+
+	return jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		key := kr.legacy
+		if kid, ok := t.Header["kid"].(string); ok {
+			key = kr.keys[kid]
+		}
+		if key == nil || key.retired(time.Now()) || key.Method.Alg() != t.Method.Alg() {
+			return nil, ErrUnknownKey
+		}
+		return key.verify, nil
+	}, jwt.WithValidMethods(methods))
+}
+
+// JWK is a public key in RFC 7517 form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS publishes the public half of every asymmetric key that still verifies tokens.
+func (kr *Keyring) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	now := time.Now()
+	for _, k := range kr.keys {
+		if k.retired(now) {
+			continue
+		}
+
+		jwk := JWK{Kid: k.ID, Use: "sig", Alg: k.Method.Alg()}
+		switch pub := k.verify.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	slices.SortFunc(set.Keys, func(a, b JWK) int { return strings.Compare(a.Kid, b.Kid) })
+	return set
+}
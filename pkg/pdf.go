@@ -0,0 +1,281 @@
+package pkg
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"strings"
+)
+
+// Page sizes in points, 72 to the inch.
+const (
+	PageA4Width  = 595.28
+	PageA4Height = 841.89
+)
+
+// PDFFont is one of the standard Type 1 fonts every PDF reader ships with, so
+// documents need no embedded font files.
+type PDFFont int
+
+const (
+	Helvetica PDFFont = iota
+	HelveticaBold
+)
+
+var pdfFontNames = [...]string{"Helvetica", "Helvetica-Bold"}
+
+// Glyph widths of printable ASCII, in thousandths of the font size, from the Adobe
+// font metrics of the standard fonts.
+var pdfFontWidths = [...][95]int{
+	{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+type pdfImage struct {
+	width, height int
+	colorSpace    string
+	filter        string
+	bpc           int
+	data          []byte
+}
+
+// PDF builds a document page by page. Coordinates are in points with the origin at
+// the top left corner of the page, y growing downwards.
+type PDF struct {
+	width, height float64
+	pages         []*bytes.Buffer
+	images        []pdfImage
+}
+
+func NewPDF(width, height float64) *PDF {
+	return &PDF{width: width, height: height}
+}
+
+// AddPage starts a new page that following drawing calls go to.
+func (p *PDF) AddPage() {
+	p.pages = append(p.pages, &bytes.Buffer{})
+}
+
+func (p *PDF) page() *bytes.Buffer {
+	if len(p.pages) == 0 {
+		p.AddPage()
+	}
+	return p.pages[len(p.pages)-1]
+}
+
+// Rect fills a rectangle with an RGB color.
+func (p *PDF) Rect(x, y, w, h float64, fill color.RGBA) {
+	fmt.Fprintf(p.page(), "%s rg %s %s %s %s re f\n",
+		pdfColor(fill), pdfNum(x), pdfNum(p.height-y-h), pdfNum(w), pdfNum(h))
+}
+
+// Line strokes a straight line.
+func (p *PDF) Line(x1, y1, x2, y2, width float64, stroke color.RGBA) {
+	fmt.Fprintf(p.page(), "%s RG %s w %s %s m %s %s l S\n",
+		pdfColor(stroke), pdfNum(width), pdfNum(x1), pdfNum(p.height-y1), pdfNum(x2), pdfNum(p.height-y2))
+}
+
+// Text draws a single line of text with its baseline at y. Characters outside
+// Windows-1252 are printed as question marks.
+func (p *PDF) Text(x, y float64, font PDFFont, size float64, fill color.RGBA, text string) {
+	fmt.Fprintf(p.page(), "BT %s rg /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		pdfColor(fill), font+1, pdfNum(size), pdfNum(x), pdfNum(p.height-y), pdfEscape(text))
+}
+
+// TextWidth measures text as Text would draw it.
+func (p *PDF) TextWidth(font PDFFont, size float64, text string) float64 {
+	total := 0
+	for _, r := range text {
+		if r >= 32 && r < 127 {
+			total += pdfFontWidths[font][r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Image draws img scaled into the given box. Transparent pixels are blended onto white.
+func (p *PDF) Image(img image.Image, x, y, w, h float64) {
+	bounds := img.Bounds()
+	rgb := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			r, g, b, a := img.At(px, py).RGBA()
+			white := 0xffff - a
+			rgb = append(rgb, byte((r+white)>>8), byte((g+white)>>8), byte((b+white)>>8))
+		}
+	}
+
+	var data bytes.Buffer
+	zw := zlib.NewWriter(&data)
+	zw.Write(rgb)
+	zw.Close()
+
+	p.drawImage(pdfImage{
+		width: bounds.Dx(), height: bounds.Dy(),
+		colorSpace: "/DeviceRGB", filter: "/FlateDecode", bpc: 8,
+		data: data.Bytes(),
+	}, x, y, w, h)
+}
+
+// JPEG draws an encoded JPEG scaled into the given box without decoding it.
+func (p *PDF) JPEG(data []byte, x, y, w, h float64) error {
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	colorSpace := "/DeviceRGB"
+	switch cfg.ColorModel {
+	case color.GrayModel:
+		colorSpace = "/DeviceGray"
+	case color.CMYKModel:
+		colorSpace = "/DeviceCMYK"
+	}
+
+	p.drawImage(pdfImage{
+		width: cfg.Width, height: cfg.Height,
+		colorSpace: colorSpace, filter: "/DCTDecode", bpc: 8,
+		data: data,
+	}, x, y, w, h)
+	return nil
+}
+
+func (p *PDF) drawImage(img pdfImage, x, y, w, h float64) {
+	p.images = append(p.images, img)
+	fmt.Fprintf(p.page(), "q %s 0 0 %s %s %s cm /Im%d Do Q\n",
+		pdfNum(w), pdfNum(h), pdfNum(x), pdfNum(p.height-y-h), len(p.images))
+}
+
+// Bytes serializes the document.
+func (p *PDF) Bytes() []byte {
+	p.page()
+
+	// Objects are numbered up front: catalog, page tree, fonts, images, then a page
+	// and its content stream per page.
+	fontObj := 3
+	imageObj := fontObj + len(pdfFontNames)
+	pageObj := imageObj + len(p.images)
+
+	var out bytes.Buffer
+	offsets := []int{0}
+	begin := func(n int) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n", n)
+	}
+	stream := func(dict string, data []byte) {
+		fmt.Fprintf(&out, "<< %s /Length %d >>\nstream\n", dict, len(data))
+		out.Write(data)
+		out.WriteString("\nendstream\nendobj\n")
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	begin(1)
+	out.WriteString("<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+
+	begin(2)
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageObj+2*i)
+	}
+	fmt.Fprintf(&out, "<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(p.pages))
+
+	for i, name := range pdfFontNames {
+		begin(fontObj + i)
+		fmt.Fprintf(&out, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\nendobj\n", name)
+	}
+
+	for i, img := range p.images {
+		begin(imageObj + i)
+		stream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent %d /Filter %s",
+			img.width, img.height, img.colorSpace, img.bpc, img.filter), img.data)
+	}
+
+	var resources strings.Builder
+	resources.WriteString("/Font <<")
+	for i := range pdfFontNames {
+		fmt.Fprintf(&resources, " /F%d %d 0 R", i+1, fontObj+i)
+	}
+	resources.WriteString(" >>")
+	if len(p.images) > 0 {
+		resources.WriteString(" /XObject <<")
+		for i := range p.images {
+			fmt.Fprintf(&resources, " /Im%d %d 0 R", i+1, imageObj+i)
+		}
+		resources.WriteString(" >>")
+	}
+
+	for i, content := range p.pages {
+		begin(pageObj + 2*i)
+		fmt.Fprintf(&out, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << %s >> /Contents %d 0 R >>\nendobj\n",
+			pdfNum(p.width), pdfNum(p.height), resources.String(), pageObj+2*i+1)
+
+		var data bytes.Buffer
+		zw := zlib.NewWriter(&data)
+		zw.Write(content.Bytes())
+		zw.Close()
+
+		begin(pageObj + 2*i + 1)
+		stream("/Filter /FlateDecode", data.Bytes())
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets))
+	for _, offset := range offsets[1:] {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets), xref)
+
+	return out.Bytes()
+}
+
+func pdfNum(f float64) string {
+	s := fmt.Sprintf("%.2f", f)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+func pdfColor(c color.RGBA) string {
+	return fmt.Sprintf("%s %s %s", pdfNum(float64(c.R)/255), pdfNum(float64(c.G)/255), pdfNum(float64(c.B)/255))
+}
+
+// pdfEscape encodes text as a Windows-1252 string literal.
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
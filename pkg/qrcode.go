@@ -0,0 +1,534 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// QR codes are encoded in byte mode with error correction level M, which keeps
+// tickets readable on scratched or dimmed phone screens.
+var (
+	qrEccCodewordsPerBlock     = [41]int{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28}
+	qrNumErrorCorrectionBlocks = [41]int{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49}
+)
+
+const (
+	qrFormatBitsM = 0
+	qrQuietZone   = 4
+)
+
+var ErrQRDataTooLong = errors.New("data too long for a QR code")
+
+type QRCode struct {
+	version    int
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// NewQRCode encodes text into the smallest QR code version that fits it.
+func NewQRCode(text string) (*QRCode, error) {
+	data := []byte(text)
+
+	version := 0
+	for v := 1; v <= 40; v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if len(data) < 1<<countBits && 4+countBits+8*len(data) <= qrNumDataCodewords(v)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrQRDataTooLong
+	}
+
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+
+	var bb qrBitBuffer
+	bb.append(0x4, 4)
+	bb.append(len(data), countBits)
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+
+	capacity := qrNumDataCodewords(version) * 8
+	bb.append(0, min(4, capacity-len(bb)))
+	bb.append(0, (8-len(bb)%8)%8)
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	codewords := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			codewords[i>>3] |= 1 << (7 - i&7)
+		}
+	}
+
+	q := &QRCode{version: version, size: version*4 + 17}
+	q.modules = make([][]bool, q.size)
+	q.isFunction = make([][]bool, q.size)
+	for i := range q.modules {
+		q.modules[i] = make([]bool, q.size)
+		q.isFunction[i] = make([]bool, q.size)
+	}
+
+	q.drawFunctionPatterns()
+	q.drawCodewords(q.addEccAndInterleave(codewords))
+
+	bestMask, minPenalty := 0, -1
+	for mask := range 8 {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if penalty := q.penaltyScore(); minPenalty < 0 || penalty < minPenalty {
+			bestMask, minPenalty = mask, penalty
+		}
+		q.applyMask(mask)
+	}
+	q.applyMask(bestMask)
+	q.drawFormatBits(bestMask)
+
+	return q, nil
+}
+
+// Image renders the code with scale pixels per module and the standard quiet zone.
+func (q *QRCode) Image(scale int) *image.Gray {
+	if scale < 1 {
+		scale = 1
+	}
+
+	dim := (q.size + qrQuietZone*2) * scale
+	img := image.NewGray(image.Rect(0, 0, dim, dim))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+
+	for y := range q.size {
+		for x := range q.size {
+			if !q.modules[y][x] {
+				continue
+			}
+			for dy := range scale {
+				for dx := range scale {
+					img.SetGray((x+qrQuietZone)*scale+dx, (y+qrQuietZone)*scale+dy, color.Gray{Y: 0})
+				}
+			}
+		}
+	}
+	return img
+}
+
+// PNG encodes Image as a PNG file.
+func (q *QRCode) PNG(scale int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, q.Image(scale)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG renders the code as a single path; scale is the width of a module in pixels.
+func (q *QRCode) SVG(scale int) string {
+	if scale < 1 {
+		scale = 1
+	}
+
+	dim := q.size + qrQuietZone*2
+
+	var path strings.Builder
+	for y := range q.size {
+		for x := range q.size {
+			if q.modules[y][x] {
+				fmt.Fprintf(&path, "M%d,%dh1v1h-1z", x+qrQuietZone, y+qrQuietZone)
+			}
+		}
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">
+<rect width="100%%" height="100%%" fill="#FFFFFF"/>
+<path d="%s" fill="#000000"/>
+</svg>
+`, dim*scale, dim*scale, dim, dim, path.String())
+}
+
+func (q *QRCode) setFunctionModule(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.isFunction[y][x] = true
+}
+
+func (q *QRCode) drawFunctionPatterns() {
+	for i := range q.size {
+		q.setFunctionModule(6, i, i%2 == 0)
+		q.setFunctionModule(i, 6, i%2 == 0)
+	}
+
+	q.drawFinderPattern(3, 3)
+	q.drawFinderPattern(q.size-4, 3)
+	q.drawFinderPattern(3, q.size-4)
+
+	positions := q.alignmentPatternPositions()
+	last := len(positions) - 1
+	for i := range positions {
+		for j := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			q.drawAlignmentPattern(positions[i], positions[j])
+		}
+	}
+
+	q.drawFormatBits(0)
+	q.drawVersion()
+}
+
+func (q *QRCode) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= q.size || yy < 0 || yy >= q.size {
+				continue
+			}
+			dist := max(qrAbs(dx), qrAbs(dy))
+			q.setFunctionModule(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (q *QRCode) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.setFunctionModule(x+dx, y+dy, max(qrAbs(dx), qrAbs(dy)) != 1)
+		}
+	}
+}
+
+func (q *QRCode) alignmentPatternPositions() []int {
+	if q.version == 1 {
+		return nil
+	}
+
+	numAlign := q.version/7 + 2
+	step := (q.version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+
+	positions := make([]int, numAlign)
+	positions[0] = 6
+	for i, pos := numAlign-1, q.size-7; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+func (q *QRCode) drawFormatBits(mask int) {
+	data := qrFormatBitsM<<3 | mask
+	rem := data
+	for range 10 {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	for i := 0; i <= 5; i++ {
+		q.setFunctionModule(8, i, qrBit(bits, i))
+	}
+	q.setFunctionModule(8, 7, qrBit(bits, 6))
+	q.setFunctionModule(8, 8, qrBit(bits, 7))
+	q.setFunctionModule(7, 8, qrBit(bits, 8))
+	for i := 9; i < 15; i++ {
+		q.setFunctionModule(14-i, 8, qrBit(bits, i))
+	}
+
+	for i := range 8 {
+		q.setFunctionModule(q.size-1-i, 8, qrBit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunctionModule(8, q.size-15+i, qrBit(bits, i))
+	}
+	q.setFunctionModule(8, q.size-8, true)
+}
+
+func (q *QRCode) drawVersion() {
+	if q.version < 7 {
+		return
+	}
+
+	rem := q.version
+	for range 12 {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := q.version<<12 | rem
+
+	for i := range 18 {
+		bit := qrBit(bits, i)
+		a, b := q.size-11+i%3, i/3
+		q.setFunctionModule(a, b, bit)
+		q.setFunctionModule(b, a, bit)
+	}
+}
+
+// addEccAndInterleave splits the data into blocks, appends Reed-Solomon codewords
+// to each block and interleaves them in the order they are placed in the symbol.
+func (q *QRCode) addEccAndInterleave(data []byte) []byte {
+	numBlocks := qrNumErrorCorrectionBlocks[q.version]
+	blockEccLen := qrEccCodewordsPerBlock[q.version]
+	rawCodewords := qrNumRawDataModules(q.version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := qrReedSolomonDivisor(blockEccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		datLen := shortBlockLen - blockEccLen
+		if i >= numShortBlocks {
+			datLen++
+		}
+		dat := data[k : k+datLen]
+		k += datLen
+
+		block := append([]byte{}, dat...)
+		if i < numShortBlocks {
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, qrReedSolomonRemainder(dat, divisor)...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockEccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+func (q *QRCode) drawCodewords(data []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range q.size {
+			for j := range 2 {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if !q.isFunction[y][x] && i < len(data)*8 {
+					q.modules[y][x] = qrBit(int(data[i>>3]), 7-i&7)
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (q *QRCode) applyMask(mask int) {
+	for y := range q.size {
+		for x := range q.size {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !q.isFunction[y][x] {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+func (q *QRCode) penaltyScore() int {
+	const (
+		penaltyN1 = 3
+		penaltyN2 = 3
+		penaltyN3 = 40
+		penaltyN4 = 10
+	)
+
+	result := 0
+	for _, vertical := range []bool{false, true} {
+		for a := range q.size {
+			runColor := false
+			run := 0
+			var history [7]int
+			for b := range q.size {
+				module := q.modules[a][b]
+				if vertical {
+					module = q.modules[b][a]
+				}
+				if module == runColor {
+					run++
+					if run == 5 {
+						result += penaltyN1
+					} else if run > 5 {
+						result++
+					}
+				} else {
+					q.finderPenaltyAddHistory(run, &history)
+					if !runColor {
+						result += q.finderPenaltyCountPatterns(&history) * penaltyN3
+					}
+					runColor = module
+					run = 1
+				}
+			}
+			result += q.finderPenaltyTerminateAndCount(runColor, run, &history) * penaltyN3
+		}
+	}
+
+	for y := 0; y < q.size-1; y++ {
+		for x := 0; x < q.size-1; x++ {
+			c := q.modules[y][x]
+			if c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+				result += penaltyN2
+			}
+		}
+	}
+
+	dark := 0
+	for _, row := range q.modules {
+		for _, module := range row {
+			if module {
+				dark++
+			}
+		}
+	}
+	total := q.size * q.size
+	k := (qrAbs(dark*20-total*10)+total-1)/total - 1
+	result += k * penaltyN4
+
+	return result
+}
+
+func (q *QRCode) finderPenaltyCountPatterns(history *[7]int) int {
+	n := history[1]
+	core := n > 0 && history[2] == n && history[3] == n*3 && history[4] == n && history[5] == n
+	count := 0
+	if core && history[0] >= n*4 && history[6] >= n {
+		count++
+	}
+	if core && history[6] >= n*4 && history[0] >= n {
+		count++
+	}
+	return count
+}
+
+func (q *QRCode) finderPenaltyTerminateAndCount(runColor bool, run int, history *[7]int) int {
+	if runColor {
+		q.finderPenaltyAddHistory(run, history)
+		run = 0
+	}
+	run += q.size
+	q.finderPenaltyAddHistory(run, history)
+	return q.finderPenaltyCountPatterns(history)
+}
+
+func (q *QRCode) finderPenaltyAddHistory(run int, history *[7]int) {
+	if history[0] == 0 {
+		run += q.size
+	}
+	copy(history[1:], history[:6])
+	history[0] = run
+}
+
+type qrBitBuffer []bool
+
+func (bb *qrBitBuffer) append(val, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*bb = append(*bb, qrBit(val, i))
+	}
+}
+
+func qrNumRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func qrNumDataCodewords(version int) int {
+	return qrNumRawDataModules(version)/8 - qrEccCodewordsPerBlock[version]*qrNumErrorCorrectionBlocks[version]
+}
+
+func qrReedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for range degree {
+		for j := range result {
+			result[j] = qrMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = qrMultiply(root, 0x02)
+	}
+	return result
+}
+
+func qrReedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= qrMultiply(d, factor)
+		}
+	}
+	return result
+}
+
+// qrMultiply multiplies two elements of GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func qrMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+func qrBit(x, i int) bool {
+	return (x>>i)&1 != 0
+}
+
+func qrAbs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+const ticketPrefix = "TKT1"
+
+var (
+	ErrInvalidTicket = errors.New("invalid ticket")
+	ErrTicketExpired = errors.New("ticket expired")
+)
+
+// TicketClaims is the payload of the token printed in a ticket's QR code. The token
+// is "TKT1.<payload>.<signature>", both parts base64url encoded, signed with
+// HMAC-SHA256 over "TKT1.<payload>".
+type TicketClaims struct {
+	OrderID    int      `json:"oid"`
+	ScheduleID int      `json:"sid"`
+	SeatCodes  []string `json:"seats"`
+	ExpiresAt  int64    `json:"exp"`
+	Nonce      string   `json:"nonce"`
+}
+
+func NewTicketClaims(orderID, scheduleID int, seatCodes []string, expiresAt time.Time) *TicketClaims {
+	return &TicketClaims{
+		OrderID:    orderID,
+		ScheduleID: scheduleID,
+		SeatCodes:  seatCodes,
+		ExpiresAt:  expiresAt.Unix(),
+	}
+}
+
+func (t *TicketClaims) GenerateToken() (string, error) {
+	secretKey := os.Getenv("TICKET_SECRET")
+	if secretKey == "" {
+		return "", errors.New("no ticket secret found")
+	}
+
+	if t.Nonce == "" {
+		nonce := make([]byte, 8)
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		t.Nonce = base64.RawURLEncoding.EncodeToString(nonce)
+	}
+
+	payload, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+
+	signed := ticketPrefix + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signTicket(signed, secretKey)), nil
+}
+
+func (t *TicketClaims) VerifyToken(token string) error {
+	secretKey := os.Getenv("TICKET_SECRET")
+	if secretKey == "" {
+		return errors.New("no ticket secret found")
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != ticketPrefix {
+		return ErrInvalidTicket
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return ErrInvalidTicket
+	}
+	if !hmac.Equal(signature, signTicket(parts[0]+"."+parts[1], secretKey)) {
+		return ErrInvalidTicket
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ErrInvalidTicket
+	}
+	if err := json.Unmarshal(payload, t); err != nil {
+		return ErrInvalidTicket
+	}
+
+	if time.Now().Unix() > t.ExpiresAt {
+		return ErrTicketExpired
+	}
+	return nil
+}
+
+func signTicket(signed, secretKey string) []byte {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(signed))
+	return mac.Sum(nil)
+}